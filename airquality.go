@@ -0,0 +1,171 @@
+package weathercli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// airQualityFields are requested for both current and hourly air quality.
+// Pollen is only modelled for Europe and is null elsewhere.
+const airQualityFields = "pm2_5,pm10,ozone,nitrogen_dioxide,sulphur_dioxide,carbon_monoxide,european_aqi,us_aqi,alder_pollen,birch_pollen,grass_pollen,mugwort_pollen,olive_pollen,ragweed_pollen"
+
+// AirQuality fetches current air quality and an hourly forecast for the
+// given number of days (1-7) by coordinates.
+func (c *Client) AirQuality(ctx context.Context, lat, lon float64, days int, loc *Location) (*AirQuality, error) {
+	u, err := url.Parse(c.airQualityBaseURL + "/air-quality")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("latitude", fmt.Sprintf("%.4f", lat))
+	q.Set("longitude", fmt.Sprintf("%.4f", lon))
+	q.Set("current", airQualityFields)
+	q.Set("hourly", airQualityFields)
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", fmt.Sprintf("%d", days))
+	u.RawQuery = q.Encode()
+
+	var result struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Timezone  string  `json:"timezone"`
+		Current   struct {
+			Time string `json:"time"`
+			airQualityValues
+		} `json:"current"`
+		Hourly struct {
+			Time            []string   `json:"time"`
+			PM25            []*float64 `json:"pm2_5"`
+			PM10            []*float64 `json:"pm10"`
+			Ozone           []*float64 `json:"ozone"`
+			NitrogenDioxide []*float64 `json:"nitrogen_dioxide"`
+			SulphurDioxide  []*float64 `json:"sulphur_dioxide"`
+			CarbonMonoxide  []*float64 `json:"carbon_monoxide"`
+			EuropeanAQI     []*float64 `json:"european_aqi"`
+			USAQI           []*float64 `json:"us_aqi"`
+			AlderPollen     []*float64 `json:"alder_pollen"`
+			BirchPollen     []*float64 `json:"birch_pollen"`
+			GrassPollen     []*float64 `json:"grass_pollen"`
+			MugwortPollen   []*float64 `json:"mugwort_pollen"`
+			OlivePollen     []*float64 `json:"olive_pollen"`
+			RagweedPollen   []*float64 `json:"ragweed_pollen"`
+		} `json:"hourly"`
+	}
+
+	meta, err := c.getJSON(ctx, u, "air quality", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	tz := loadTimezone(result.Timezone)
+
+	t, err := time.ParseInLocation(timeLayout, result.Current.Time, tz)
+	if err != nil {
+		return nil, &DecodeError{Endpoint: "air quality", Err: fmt.Errorf("failed to parse time %q: %w", result.Current.Time, err)}
+	}
+
+	aq := &AirQuality{
+		SchemaVersion: SchemaVersion,
+		Current:       result.Current.reading(t),
+	}
+	aq.Stale, aq.CachedAt = meta.staleSince()
+
+	if loc != nil {
+		aq.Location = *loc
+		aq.Location.Timezone = result.Timezone
+	} else {
+		aq.Location = Location{
+			Latitude:  result.Latitude,
+			Longitude: result.Longitude,
+			Timezone:  result.Timezone,
+		}
+	}
+
+	h := result.Hourly
+	aq.Hourly = make([]AirQualityReading, len(h.Time))
+	for i := range h.Time {
+		t, err := time.ParseInLocation(timeLayout, h.Time[i], tz)
+		if err != nil {
+			return nil, &DecodeError{Endpoint: "air quality", Err: fmt.Errorf("failed to parse hourly time %q: %w", h.Time[i], err)}
+		}
+		v := airQualityValues{
+			PM25:            at(h.PM25, i),
+			PM10:            at(h.PM10, i),
+			Ozone:           at(h.Ozone, i),
+			NitrogenDioxide: at(h.NitrogenDioxide, i),
+			SulphurDioxide:  at(h.SulphurDioxide, i),
+			CarbonMonoxide:  at(h.CarbonMonoxide, i),
+			EuropeanAQI:     at(h.EuropeanAQI, i),
+			USAQI:           at(h.USAQI, i),
+			AlderPollen:     at(h.AlderPollen, i),
+			BirchPollen:     at(h.BirchPollen, i),
+			GrassPollen:     at(h.GrassPollen, i),
+			MugwortPollen:   at(h.MugwortPollen, i),
+			OlivePollen:     at(h.OlivePollen, i),
+			RagweedPollen:   at(h.RagweedPollen, i),
+		}
+		aq.Hourly[i] = v.reading(t)
+	}
+
+	return aq, nil
+}
+
+// airQualityValues holds one time step of the raw air quality response.
+// Values are pointers because the API returns null where a variable is not
+// modelled.
+type airQualityValues struct {
+	PM25            *float64 `json:"pm2_5"`
+	PM10            *float64 `json:"pm10"`
+	Ozone           *float64 `json:"ozone"`
+	NitrogenDioxide *float64 `json:"nitrogen_dioxide"`
+	SulphurDioxide  *float64 `json:"sulphur_dioxide"`
+	CarbonMonoxide  *float64 `json:"carbon_monoxide"`
+	EuropeanAQI     *float64 `json:"european_aqi"`
+	USAQI           *float64 `json:"us_aqi"`
+	AlderPollen     *float64 `json:"alder_pollen"`
+	BirchPollen     *float64 `json:"birch_pollen"`
+	GrassPollen     *float64 `json:"grass_pollen"`
+	MugwortPollen   *float64 `json:"mugwort_pollen"`
+	OlivePollen     *float64 `json:"olive_pollen"`
+	RagweedPollen   *float64 `json:"ragweed_pollen"`
+}
+
+func (v airQualityValues) reading(t time.Time) AirQualityReading {
+	r := AirQualityReading{
+		Time:            t,
+		PM25:            v.PM25,
+		PM10:            v.PM10,
+		Ozone:           v.Ozone,
+		NitrogenDioxide: v.NitrogenDioxide,
+		SulphurDioxide:  v.SulphurDioxide,
+		CarbonMonoxide:  v.CarbonMonoxide,
+		EuropeanAQI:     aqiIndex(v.EuropeanAQI),
+		USAQI:           aqiIndex(v.USAQI),
+	}
+
+	pollen := Pollen{
+		Alder:   v.AlderPollen,
+		Birch:   v.BirchPollen,
+		Grass:   v.GrassPollen,
+		Mugwort: v.MugwortPollen,
+		Olive:   v.OlivePollen,
+		Ragweed: v.RagweedPollen,
+	}
+	if pollen != (Pollen{}) {
+		r.Pollen = &pollen
+	}
+
+	return r
+}
+
+// aqiIndex rounds an AQI to the nearest whole number, keeping null as nil.
+func aqiIndex(p *float64) *int {
+	if p == nil {
+		return nil
+	}
+	i := int(*p + 0.5)
+	return &i
+}
@@ -0,0 +1,87 @@
+package weathercli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAirQuality(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"latitude":52.52,"longitude":13.42,"timezone":"Europe/Berlin",
+			"current":{"time":"2024-04-10T14:00","pm2_5":8.4,"pm10":14.2,"ozone":92.0,"nitrogen_dioxide":11.3,
+				"sulphur_dioxide":1.2,"carbon_monoxide":180.0,"european_aqi":42,"us_aqi":51,
+				"alder_pollen":null,"birch_pollen":120.5,"grass_pollen":0.3,"mugwort_pollen":null,"olive_pollen":null,"ragweed_pollen":null},
+			"hourly":{"time":["2024-04-10T14:00","2024-04-10T15:00"],
+				"pm2_5":[8.4,null],"european_aqi":[42,45],"us_aqi":[51,null],
+				"birch_pollen":[null,null],"grass_pollen":[null,null]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{AirQualityBaseURL: srv.URL})
+	loc := &Location{Name: "Berlin"}
+	aq, err := client.AirQuality(context.Background(), 52.52, 13.41, 1, loc)
+	if err != nil {
+		t.Fatalf("AirQuality failed: %v", err)
+	}
+
+	if gotPath != "/air-quality" {
+		t.Errorf("path = %q, want /air-quality", gotPath)
+	}
+	if aq.Location.Name != "Berlin" || aq.Location.Timezone != "Europe/Berlin" {
+		t.Errorf("Location = %+v, want Berlin in Europe/Berlin", aq.Location)
+	}
+
+	cur := aq.Current
+	if cur.PM25 == nil || *cur.PM25 != 8.4 || cur.EuropeanAQI == nil || *cur.EuropeanAQI != 42 || cur.USAQI == nil || *cur.USAQI != 51 {
+		t.Errorf("Current = %+v", cur)
+	}
+	if cur.Pollen == nil || cur.Pollen.Birch == nil || *cur.Pollen.Birch != 120.5 {
+		t.Fatalf("Current.Pollen = %+v, want birch 120.5", cur.Pollen)
+	}
+	if cur.Pollen.Alder != nil {
+		t.Errorf("Current.Pollen.Alder = %v, want nil for null", *cur.Pollen.Alder)
+	}
+
+	if len(aq.Hourly) != 2 {
+		t.Fatalf("Expected 2 hours, got %d", len(aq.Hourly))
+	}
+	if h := aq.Hourly[1]; h.EuropeanAQI == nil || *h.EuropeanAQI != 45 {
+		t.Errorf("Hourly[1].EuropeanAQI = %v, want 45", h.EuropeanAQI)
+	}
+	if h := aq.Hourly[1]; h.PM25 != nil || h.USAQI != nil || h.Ozone != nil {
+		t.Errorf("Hourly[1] = %+v, want nil readings for nulls and missing variables", h)
+	}
+	if aq.Hourly[0].Pollen != nil {
+		t.Errorf("Hourly[0].Pollen = %+v, want nil when no species are modelled", aq.Hourly[0].Pollen)
+	}
+}
+
+func TestAQILevels(t *testing.T) {
+	eu := []struct {
+		aqi  int
+		want string
+	}{
+		{0, "Good"}, {20, "Good"}, {21, "Fair"}, {55, "Moderate"}, {80, "Poor"}, {100, "Very poor"}, {101, "Extremely poor"},
+	}
+	for _, tt := range eu {
+		if got := EuropeanAQILevel(tt.aqi); got != tt.want {
+			t.Errorf("EuropeanAQILevel(%d) = %q, want %q", tt.aqi, got, tt.want)
+		}
+	}
+
+	us := []struct {
+		aqi  int
+		want string
+	}{
+		{50, "Good"}, {51, "Moderate"}, {150, "Unhealthy for sensitive groups"}, {200, "Unhealthy"}, {300, "Very unhealthy"}, {301, "Hazardous"},
+	}
+	for _, tt := range us {
+		if got := USAQILevel(tt.aqi); got != tt.want {
+			t.Errorf("USAQILevel(%d) = %q, want %q", tt.aqi, got, tt.want)
+		}
+	}
+}
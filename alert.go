@@ -0,0 +1,285 @@
+package weathercli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a threshold condition evaluated against a Forecast, such as
+// "temp_min < 0" or "precip_prob >= 70 within 12h". Thresholds are in the
+// forecast's units.
+type Rule struct {
+	Expr      string        `json:"expr"`
+	Metric    string        `json:"metric"`
+	Op        string        `json:"op"`
+	Threshold float64       `json:"threshold"`
+	Within    time.Duration `json:"-"` // 0 means the whole forecast; see MarshalJSON
+
+	hourlyWindow bool // Within was given in hours
+}
+
+// MarshalJSON encodes Within in rule syntax, as "within": "12h" or "2d".
+func (r Rule) MarshalJSON() ([]byte, error) {
+	type rule Rule // without the MarshalJSON method
+	var within string
+	switch {
+	case r.Within <= 0:
+	case r.hourlyWindow || r.Within%(24*time.Hour) != 0:
+		within = fmt.Sprintf("%dh", int(r.Within.Hours()))
+	default:
+		within = fmt.Sprintf("%dd", int(r.Within.Hours())/24)
+	}
+	return json.Marshal(struct {
+		rule
+		Within string `json:"within,omitempty"`
+	}{rule(r), within})
+}
+
+// RuleResult reports whether a rule fired and where.
+type RuleResult struct {
+	Rule    Rule        `json:"rule"`
+	Fired   bool        `json:"fired"`
+	Matches []RuleMatch `json:"matches,omitempty"`
+}
+
+// RuleMatch is a forecast entry that satisfied a rule.
+type RuleMatch struct {
+	Series string    `json:"series"` // "daily" or "hourly"
+	Time   time.Time `json:"time"`
+	Value  float64   `json:"value"`
+}
+
+// ruleMetric extracts a value from daily and/or hourly entries. A nil
+// extractor means the metric does not exist in that series.
+type ruleMetric struct {
+	daily  func(DailyForecast) float64
+	hourly func(HourlyForecast) float64
+}
+
+// ruleMetrics lists the metrics rules can use. Daily extremes also apply
+// to hourly entries, so "temp_min < 0 within 12h" checks each hour.
+var ruleMetrics = map[string]ruleMetric{
+	"temp": {
+		hourly: func(h HourlyForecast) float64 { return h.Temperature },
+	},
+	"temp_max": {
+		daily:  func(d DailyForecast) float64 { return d.TempMax },
+		hourly: func(h HourlyForecast) float64 { return h.Temperature },
+	},
+	"temp_min": {
+		daily:  func(d DailyForecast) float64 { return d.TempMin },
+		hourly: func(h HourlyForecast) float64 { return h.Temperature },
+	},
+	"apparent": {
+		hourly: func(h HourlyForecast) float64 { return h.Apparent },
+	},
+	"apparent_max": {
+		daily:  func(d DailyForecast) float64 { return d.ApparentMax },
+		hourly: func(h HourlyForecast) float64 { return h.Apparent },
+	},
+	"apparent_min": {
+		daily:  func(d DailyForecast) float64 { return d.ApparentMin },
+		hourly: func(h HourlyForecast) float64 { return h.Apparent },
+	},
+	"humidity": {
+		hourly: func(h HourlyForecast) float64 { return float64(h.Humidity) },
+	},
+	"precip": {
+		daily:  func(d DailyForecast) float64 { return d.Precipitation },
+		hourly: func(h HourlyForecast) float64 { return h.Precipitation },
+	},
+	"rain": {
+		daily:  func(d DailyForecast) float64 { return d.Rain },
+		hourly: func(h HourlyForecast) float64 { return h.Rain },
+	},
+	"snowfall": {
+		daily:  func(d DailyForecast) float64 { return d.Snowfall },
+		hourly: func(h HourlyForecast) float64 { return h.Snowfall },
+	},
+	"precip_prob": {
+		daily:  func(d DailyForecast) float64 { return float64(d.PrecipProb) },
+		hourly: func(h HourlyForecast) float64 { return float64(h.PrecipProb) },
+	},
+	"wind_speed": {
+		hourly: func(h HourlyForecast) float64 { return h.WindSpeed },
+	},
+	"wind_speed_max": {
+		daily:  func(d DailyForecast) float64 { return d.WindSpeedMax },
+		hourly: func(h HourlyForecast) float64 { return h.WindSpeed },
+	},
+	"pressure": {
+		hourly: func(h HourlyForecast) float64 { return h.Pressure },
+	},
+	"cloud_cover": {
+		hourly: func(h HourlyForecast) float64 { return float64(h.CloudCover) },
+	},
+	"uv_index": {
+		hourly: func(h HourlyForecast) float64 { return h.UVIndex },
+	},
+	"uv_index_max": {
+		daily:  func(d DailyForecast) float64 { return d.UVIndexMax },
+		hourly: func(h HourlyForecast) float64 { return h.UVIndex },
+	},
+}
+
+// RuleMetrics returns the metric names rules can use, sorted.
+func RuleMetrics() []string {
+	names := make([]string, 0, len(ruleMetrics))
+	for name := range ruleMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var rulePattern = regexp.MustCompile(`^([a-z_0-9]+)\s*(<=|>=|==|!=|<|>)\s*(-?[0-9]+(?:\.[0-9]+)?)(?:\s+within\s+([0-9]+)\s*([hd]))?$`)
+
+// ParseRule parses "<metric> <op> <number> [within <N>h|<N>d]".
+func ParseRule(expr string) (Rule, error) {
+	expr = strings.Join(strings.Fields(expr), " ")
+	m := rulePattern.FindStringSubmatch(strings.ToLower(expr))
+	if m == nil {
+		return Rule{}, fmt.Errorf("invalid rule %q (want e.g. \"temp_min < 0\" or \"precip_prob >= 70 within 12h\")", expr)
+	}
+
+	if _, ok := ruleMetrics[m[1]]; !ok {
+		return Rule{}, fmt.Errorf("invalid rule %q: unknown metric %q (known: %s)", expr, m[1], strings.Join(RuleMetrics(), ", "))
+	}
+
+	threshold, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid rule %q: %w", expr, err)
+	}
+
+	rule := Rule{Expr: expr, Metric: m[1], Op: m[2], Threshold: threshold}
+	if m[4] != "" {
+		n, err := strconv.Atoi(m[4])
+		if err != nil || n <= 0 {
+			return Rule{}, fmt.Errorf("invalid rule %q: window must be positive", expr)
+		}
+		if m[5] == "d" {
+			rule.Within = time.Duration(n) * 24 * time.Hour
+		} else {
+			rule.Within = time.Duration(n) * time.Hour
+			rule.hourlyWindow = true
+		}
+	}
+
+	return rule, nil
+}
+
+// ParseRules reads one rule per line. Blank lines and lines starting with
+// '#' are ignored.
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		rule, err := ParseRule(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// UsesHourly reports whether the rule needs hourly data: its window is in
+// hours, or its metric only exists hourly.
+func (r Rule) UsesHourly() bool {
+	return ruleMetrics[r.Metric].daily == nil || r.hourlyWindow
+}
+
+// Evaluate checks the rule against the entries of f that fall in the
+// rule's window, starting at now. Rules that need hourly data use
+// f.Hourly; the rest use f.Daily, falling back to f.Hourly when the
+// forecast has no daily entries. now should be in the location's timezone.
+func (r Rule) Evaluate(f *Forecast, now time.Time) RuleResult {
+	result := RuleResult{Rule: r}
+	m, ok := ruleMetrics[r.Metric]
+	if !ok {
+		return result
+	}
+
+	useHourly := m.hourly != nil && (r.UsesHourly() || len(f.Daily) == 0)
+
+	if useHourly {
+		// Truncate works in UTC, which misplaces the hour in zones with
+		// a half-hour offset; take the hour on the forecast's clock.
+		loc := now.Location()
+		if len(f.Hourly) > 0 {
+			loc = f.Hourly[0].Time.Location()
+		}
+		local := now.In(loc)
+		hourStart := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, loc)
+		for _, h := range f.Hourly {
+			if h.Time.Before(hourStart) || (r.Within > 0 && h.Time.After(now.Add(r.Within))) {
+				continue
+			}
+			if v := m.hourly(h); r.matches(v) {
+				result.Matches = append(result.Matches, RuleMatch{Series: "hourly", Time: h.Time, Value: v})
+			}
+		}
+	} else if m.daily != nil {
+		// Daily dates are midnight UTC; compare calendar dates. A daily
+		// window of N days covers today and the following N-1 days.
+		today := calendarDate(now)
+		lastDay := today.AddDate(0, 0, int(r.Within/(24*time.Hour))-1)
+		for _, d := range f.Daily {
+			if d.Date.Before(today) || (r.Within > 0 && d.Date.After(lastDay)) {
+				continue
+			}
+			if v := m.daily(d); r.matches(v) {
+				result.Matches = append(result.Matches, RuleMatch{Series: "daily", Time: d.Date, Value: v})
+			}
+		}
+	}
+
+	result.Fired = len(result.Matches) > 0
+	return result
+}
+
+// calendarDate returns t's calendar date as midnight UTC, matching how
+// daily forecast dates are parsed.
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (r Rule) matches(v float64) bool {
+	switch r.Op {
+	case "<":
+		return v < r.Threshold
+	case "<=":
+		return v <= r.Threshold
+	case ">":
+		return v > r.Threshold
+	case ">=":
+		return v >= r.Threshold
+	case "==":
+		return v == r.Threshold
+	case "!=":
+		return v != r.Threshold
+	}
+	return false
+}
+
+// EvaluateRules evaluates each rule against f.
+func EvaluateRules(f *Forecast, rules []Rule, now time.Time) []RuleResult {
+	results := make([]RuleResult, len(rules))
+	for i, r := range rules {
+		results[i] = r.Evaluate(f, now)
+	}
+	return results
+}
@@ -0,0 +1,161 @@
+package weathercli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    Rule
+		wantErr bool
+	}{
+		{expr: "temp_min < 0", want: Rule{Expr: "temp_min < 0", Metric: "temp_min", Op: "<", Threshold: 0}},
+		{expr: "precip_prob>=70 within 12h", want: Rule{Expr: "precip_prob>=70 within 12h", Metric: "precip_prob", Op: ">=", Threshold: 70, Within: 12 * time.Hour, hourlyWindow: true}},
+		{expr: "  wind_speed_max  >  50.5 ", want: Rule{Expr: "wind_speed_max > 50.5", Metric: "wind_speed_max", Op: ">", Threshold: 50.5}},
+		{expr: "temp < -5 within 2d", want: Rule{Expr: "temp < -5 within 2d", Metric: "temp", Op: "<", Threshold: -5, Within: 48 * time.Hour}},
+		{expr: "snow_depth > 1", wantErr: true},
+		{expr: "temp_min <", wantErr: true},
+		{expr: "temp_min < 0 within 0h", wantErr: true},
+		{expr: "temp_min ~ 0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRule(tt.expr)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRule(%q) = %+v, want error", tt.expr, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRule(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRule(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	input := "# frost watch\ntemp_min < 0\n\nwind_speed_max > 50\n"
+	rules, err := ParseRules(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRules failed: %v", err)
+	}
+	if len(rules) != 2 || rules[1].Metric != "wind_speed_max" {
+		t.Errorf("ParseRules = %+v, want 2 rules", rules)
+	}
+
+	_, err = ParseRules(strings.NewReader("temp_min < 0\nbogus\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ParseRules error = %v, want line 2", err)
+	}
+}
+
+func TestRuleJSON(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{"temp_min < 0", `{"expr":"temp_min \u003c 0","metric":"temp_min","op":"\u003c","threshold":0}`},
+		{"precip_prob >= 70 within 24h", `{"expr":"precip_prob \u003e= 70 within 24h","metric":"precip_prob","op":"\u003e=","threshold":70,"within":"24h"}`},
+		{"temp < -5 within 2d", `{"expr":"temp \u003c -5 within 2d","metric":"temp","op":"\u003c","threshold":-5,"within":"2d"}`},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRule(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(RuleResult{Rule: rule})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"rule":` + tt.want + `,"fired":false}`
+		if got := string(b); got != want {
+			t.Errorf("%s: got %s, want %s", tt.expr, got, want)
+		}
+	}
+}
+
+func TestRuleEvaluate(t *testing.T) {
+	tz := time.FixedZone("CET", 3600)
+	now := time.Date(2024, 1, 10, 9, 30, 0, 0, tz)
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	hour := func(h int) time.Time { return time.Date(2024, 1, 10, h, 0, 0, 0, tz) }
+
+	f := &Forecast{
+		Daily: []DailyForecast{
+			{Date: day(9), TempMin: -4},
+			{Date: day(10), TempMin: 1, PrecipProb: 80},
+			{Date: day(11), TempMin: -2},
+			{Date: day(12), TempMin: -1},
+		},
+		Hourly: []HourlyForecast{
+			{Time: hour(8), PrecipProb: 90},
+			{Time: hour(9), PrecipProb: 75},
+			{Time: hour(15), PrecipProb: 40},
+			{Time: hour(23), PrecipProb: 95},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want []time.Time
+	}{
+		// Past days are skipped.
+		{"temp_min < 0", []time.Time{day(11), day(12)}},
+		// Daily windows count calendar days from today.
+		{"temp_min < 0 within 2d", []time.Time{day(11)}},
+		// Sub-day windows use hourly data from the current hour.
+		{"precip_prob >= 70 within 12h", []time.Time{hour(9)}},
+		{"precip_prob >= 70 within 24h", []time.Time{hour(9), hour(23)}},
+		{"temp_min < -10", nil},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseRule(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error: %v", tt.expr, err)
+		}
+		got := rule.Evaluate(f, now)
+		if got.Fired != (len(tt.want) > 0) {
+			t.Errorf("%s: Fired = %v, want %v", tt.expr, got.Fired, len(tt.want) > 0)
+		}
+		if len(got.Matches) != len(tt.want) {
+			t.Errorf("%s: %d matches, want %d (%+v)", tt.expr, len(got.Matches), len(tt.want), got.Matches)
+			continue
+		}
+		for i, m := range got.Matches {
+			if !m.Time.Equal(tt.want[i]) {
+				t.Errorf("%s: match %d at %s, want %s", tt.expr, i, m.Time, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestRuleEvaluateHalfHourZone(t *testing.T) {
+	tz := time.FixedZone("IST", 5*3600+1800)
+	hour := func(h int) time.Time { return time.Date(2024, 1, 10, h, 0, 0, 0, tz) }
+	f := &Forecast{Hourly: []HourlyForecast{
+		{Time: hour(8), PrecipProb: 90},
+		{Time: hour(9), PrecipProb: 80},
+		{Time: hour(10), PrecipProb: 70},
+	}}
+	rule, err := ParseRule("precip_prob >= 70 within 2h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 09:45 local is 04:15 UTC; the current slot is 09:00 local, whatever
+	// zone now is given in.
+	for _, now := range []time.Time{time.Date(2024, 1, 10, 9, 45, 0, 0, tz), time.Date(2024, 1, 10, 4, 15, 0, 0, time.UTC)} {
+		got := rule.Evaluate(f, now)
+		if len(got.Matches) != 2 || !got.Matches[0].Time.Equal(hour(9)) || !got.Matches[1].Time.Equal(hour(10)) {
+			t.Errorf("now %s: matches = %+v, want 09:00 and 10:00", now, got.Matches)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package weathercli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// BatchOptions configures CurrentMany and ForecastMany.
+type BatchOptions struct {
+	// Concurrency bounds parallel geocoding lookups and weather requests.
+	// Defaults to 4.
+	Concurrency int
+	// BatchSize is the number of locations per multi-location weather
+	// request. Defaults to 50.
+	BatchSize int
+	// Resolve turns a query into a location. The default accepts
+	// coordinates ("52.52,13.41") and otherwise uses the best match of a
+	// location search.
+	Resolve func(ctx context.Context, query string) (Location, error)
+}
+
+func (o BatchOptions) withDefaults(c *Client) BatchOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 50
+	}
+	if o.Resolve == nil {
+		o.Resolve = c.resolveQuery
+	}
+	return o
+}
+
+// CurrentResult is the outcome for one query of CurrentMany. Exactly one of
+// Weather and Err is set.
+type CurrentResult struct {
+	Query   string
+	Weather *CurrentWeather
+	Err     error
+}
+
+// ForecastResult is the outcome for one query of ForecastMany. Exactly one
+// of Forecast and Err is set.
+type ForecastResult struct {
+	Query    string
+	Forecast *Forecast
+	Err      error
+}
+
+// CurrentMany fetches current weather for several locations. Queries are
+// geocoded concurrently and resolved locations are fetched with
+// multi-location requests. Results are in query order; a failed query sets
+// its result's Err and does not affect the others.
+func (c *Client) CurrentMany(ctx context.Context, queries []string, opts ...BatchOptions) []CurrentResult {
+	var opt BatchOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt = opt.withDefaults(c)
+
+	results := make([]CurrentResult, len(queries))
+	locs := make([]Location, len(queries))
+	for i, query := range queries {
+		results[i].Query = query
+	}
+	forEach(len(queries), opt.Concurrency, func(i int) {
+		locs[i], results[i].Err = opt.Resolve(ctx, queries[i])
+	})
+
+	batches := batchIndexes(len(queries), opt.BatchSize, func(i int) bool { return results[i].Err == nil })
+	forEach(len(batches), opt.Concurrency, func(b int) {
+		batch := batches[b]
+		weather, err := c.currentBatch(ctx, pick(locs, batch))
+		for j, i := range batch {
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Weather = weather[j]
+		}
+	})
+
+	return results
+}
+
+// ForecastMany fetches forecasts for several locations, like CurrentMany.
+func (c *Client) ForecastMany(ctx context.Context, queries []string, days int, hourly bool, opts ...BatchOptions) []ForecastResult {
+	var opt BatchOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	opt = opt.withDefaults(c)
+
+	results := make([]ForecastResult, len(queries))
+	locs := make([]Location, len(queries))
+	for i, query := range queries {
+		results[i].Query = query
+	}
+	forEach(len(queries), opt.Concurrency, func(i int) {
+		locs[i], results[i].Err = opt.Resolve(ctx, queries[i])
+	})
+
+	batches := batchIndexes(len(queries), opt.BatchSize, func(i int) bool { return results[i].Err == nil })
+	forEach(len(batches), opt.Concurrency, func(b int) {
+		batch := batches[b]
+		forecasts, err := c.forecastBatch(ctx, pick(locs, batch), days, hourly)
+		for j, i := range batch {
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Forecast = forecasts[j]
+		}
+	})
+
+	return results
+}
+
+// resolveQuery is the default BatchOptions.Resolve.
+func (c *Client) resolveQuery(ctx context.Context, query string) (Location, error) {
+	if lat, lon, err := ParseCoordinates(query); err == nil {
+		return Location{Name: fmt.Sprintf("%.4f, %.4f", lat, lon), Latitude: lat, Longitude: lon}, nil
+	}
+	locations, err := c.SearchLocation(ctx, query)
+	if err != nil {
+		return Location{}, err
+	}
+	return locations[0], nil
+}
+
+// currentBatch fetches current weather for locs in one request.
+func (c *Client) currentBatch(ctx context.Context, locs []Location) ([]*CurrentWeather, error) {
+	u, err := c.multiLocationURL(locs)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("current", currentFields)
+	u.RawQuery = q.Encode()
+
+	var raw json.RawMessage
+	meta, err := c.getJSON(ctx, u, "weather", &raw)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := decodeMultiLocation[currentResponse](raw, len(locs))
+	if err != nil {
+		return nil, err
+	}
+
+	weather := make([]*CurrentWeather, len(locs))
+	for i, r := range responses {
+		if weather[i], err = r.weather(&locs[i], c.units); err != nil {
+			return nil, err
+		}
+		weather[i].Stale, weather[i].CachedAt = meta.staleSince()
+	}
+	return weather, nil
+}
+
+// forecastBatch fetches forecasts for locs in one request.
+func (c *Client) forecastBatch(ctx context.Context, locs []Location, days int, hourly bool) ([]*Forecast, error) {
+	u, err := c.multiLocationURL(locs)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("forecast_days", fmt.Sprintf("%d", days))
+	if hourly {
+		q.Set("hourly", hourlyFields)
+	} else {
+		q.Set("daily", dailyFields)
+	}
+	u.RawQuery = q.Encode()
+
+	var raw json.RawMessage
+	meta, err := c.getJSON(ctx, u, "weather", &raw)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := decodeMultiLocation[seriesResponse](raw, len(locs))
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]*Forecast, len(locs))
+	for i := range responses {
+		loc := &locs[i]
+		if forecasts[i], err = responses[i].forecast("weather", loc.Latitude, loc.Longitude, loc, c.units); err != nil {
+			return nil, err
+		}
+		forecasts[i].Stale, forecasts[i].CachedAt = meta.staleSince()
+	}
+	return forecasts, nil
+}
+
+// multiLocationURL returns a forecast URL for locs, with comma-separated
+// coordinates.
+func (c *Client) multiLocationURL(locs []Location) (*url.URL, error) {
+	u, err := url.Parse(c.baseURL + "/forecast")
+	if err != nil {
+		return nil, err
+	}
+
+	lats := make([]string, len(locs))
+	lons := make([]string, len(locs))
+	for i, loc := range locs {
+		lats[i] = fmt.Sprintf("%.4f", loc.Latitude)
+		lons[i] = fmt.Sprintf("%.4f", loc.Longitude)
+	}
+
+	q := u.Query()
+	q.Set("latitude", strings.Join(lats, ","))
+	q.Set("longitude", strings.Join(lons, ","))
+	q.Set("timezone", "auto")
+	c.units.setQuery(q)
+	u.RawQuery = q.Encode()
+	return u, nil
+}
+
+// decodeMultiLocation decodes a multi-location response: an array with one
+// element per location, or a single object when there is one location.
+func decodeMultiLocation[T any](raw json.RawMessage, n int) ([]T, error) {
+	var items []T
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, &DecodeError{Endpoint: "weather", Err: err}
+		}
+	} else {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, &DecodeError{Endpoint: "weather", Err: err}
+		}
+		items = []T{item}
+	}
+	if len(items) != n {
+		return nil, &DecodeError{Endpoint: "weather", Err: fmt.Errorf("got %d locations, want %d", len(items), n)}
+	}
+	return items, nil
+}
+
+// batchIndexes groups the indexes in [0, n) for which ok returns true into
+// batches of at most size.
+func batchIndexes(n, size int, ok func(int) bool) [][]int {
+	var batches [][]int
+	var batch []int
+	for i := 0; i < n; i++ {
+		if !ok(i) {
+			continue
+		}
+		batch = append(batch, i)
+		if len(batch) == size {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// pick returns s[i] for each index.
+func pick[T any](s []T, indexes []int) []T {
+	out := make([]T, len(indexes))
+	for j, i := range indexes {
+		out[j] = s[i]
+	}
+	return out
+}
+
+// forEach calls fn for 0..n-1 using at most workers goroutines and waits
+// for all calls to return.
+func forEach(n, workers int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
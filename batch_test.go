@@ -0,0 +1,116 @@
+package weathercli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCurrentMany(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			switch r.URL.Query().Get("name") {
+			case "Berlin":
+				_, _ = w.Write([]byte(`{"results":[{"name":"Berlin","latitude":52.52,"longitude":13.41,"timezone":"Europe/Berlin"}]}`))
+			case "Paris":
+				_, _ = w.Write([]byte(`{"results":[{"name":"Paris","latitude":48.85,"longitude":2.35,"timezone":"Europe/Paris"}]}`))
+			default:
+				_, _ = w.Write([]byte(`{}`))
+			}
+		case "/forecast":
+			requests.Add(1)
+			lats := strings.Split(r.URL.Query().Get("latitude"), ",")
+			var items []string
+			for i, lat := range lats {
+				items = append(items, fmt.Sprintf(`{"latitude":%s,"longitude":0,"timezone":"UTC","current":{"time":"2024-01-12T09:00","temperature_2m":%d}}`, lat, i))
+			}
+			if len(items) == 1 {
+				_, _ = w.Write([]byte(items[0]))
+				return
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL, GeoBaseURL: srv.URL})
+	queries := []string{"Berlin", "Nowhere", "Paris", "10,20"}
+	results := client.CurrentMany(context.Background(), queries)
+
+	if len(results) != len(queries) {
+		t.Fatalf("got %d results, want %d", len(results), len(queries))
+	}
+	for i, r := range results {
+		if r.Query != queries[i] {
+			t.Errorf("results[%d].Query = %q, want %q", i, r.Query, queries[i])
+		}
+	}
+	if !errors.Is(results[1].Err, ErrLocationNotFound) {
+		t.Errorf("results[1].Err = %v, want ErrLocationNotFound", results[1].Err)
+	}
+	for _, i := range []int{0, 2, 3} {
+		if results[i].Err != nil {
+			t.Fatalf("results[%d].Err = %v", i, results[i].Err)
+		}
+	}
+	if got := results[0].Weather.Location.Name; got != "Berlin" {
+		t.Errorf("results[0] location = %q, want Berlin", got)
+	}
+	if got := results[2].Weather.Temperature; got != 1 {
+		t.Errorf("results[2] temperature = %v, want 1 (second item of the batch)", got)
+	}
+	if got := results[3].Weather.Location.Latitude; got != 10 {
+		t.Errorf("results[3] latitude = %v, want 10", got)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("made %d forecast requests, want 1", n)
+	}
+}
+
+func TestForecastManyBatchError(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if strings.HasPrefix(r.URL.Query().Get("latitude"), "1.0000") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":true,"reason":"bad coordinates"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"latitude":3,"longitude":3,"timezone":"UTC","daily":{"time":["2024-01-12"],"temperature_2m_max":[5],"temperature_2m_min":[1]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL})
+	results := client.ForecastMany(context.Background(), []string{"1,1", "2,2", "3,3"}, 1, false, BatchOptions{BatchSize: 2})
+
+	var apiErr *APIError
+	for i := 0; i < 2; i++ {
+		if !errors.As(results[i].Err, &apiErr) {
+			t.Errorf("results[%d].Err = %v, want APIError", i, results[i].Err)
+		}
+	}
+	if results[2].Err != nil {
+		t.Fatalf("results[2].Err = %v", results[2].Err)
+	}
+	if got := len(results[2].Forecast.Daily); got != 1 {
+		t.Errorf("results[2] has %d days, want 1", got)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestDecodeMultiLocationCount(t *testing.T) {
+	_, err := decodeMultiLocation[currentResponse]([]byte(`[{},{}]`), 3)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("err = %v, want DecodeError", err)
+	}
+}
@@ -0,0 +1,116 @@
+package weathercli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is how long weather responses are served from cache.
+	DefaultCacheTTL = 10 * time.Minute
+
+	// geocodeCacheTTL is how long geocoding results are served from cache.
+	// Place names and coordinates rarely change.
+	geocodeCacheTTL = 30 * 24 * time.Hour
+)
+
+// ErrOffline is returned in offline mode when a response is not cached.
+var ErrOffline = errors.New("offline and no cached data available")
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/weathercli, falling back to the
+// platform user cache directory.
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "weathercli"), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "weathercli"), nil
+}
+
+// fileCache stores raw API responses on disk, one file per request URL.
+type fileCache struct {
+	dir string
+}
+
+// cacheEntry is the on-disk format of a cached response.
+type cacheEntry struct {
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Body      json.RawMessage `json:"body"`
+}
+
+func (fc *fileCache) path(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(fc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the cached entry for rawURL, if any.
+func (fc *fileCache) get(rawURL string) (*cacheEntry, bool) {
+	data, err := os.ReadFile(fc.path(rawURL))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != rawURL {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// put stores body for rawURL. The file is written atomically so concurrent
+// invocations never read a partial entry.
+func (fc *fileCache) put(rawURL string, body []byte, fetchedAt time.Time) error {
+	data, err := json.Marshal(cacheEntry{URL: rawURL, FetchedAt: fetchedAt, Body: body})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(fc.path(rawURL), data)
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory, renamed into place, so readers see the old or the new content
+// but never a partial file. It creates the directory if needed.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// responseMeta describes where a decoded response came from.
+type responseMeta struct {
+	stale     bool
+	fetchedAt time.Time
+}
+
+// staleSince returns the fields used to label stale results.
+func (m responseMeta) staleSince() (bool, *time.Time) {
+	if !m.stale {
+		return false, nil
+	}
+	t := m.fetchedAt
+	return true, &t
+}
@@ -0,0 +1,97 @@
+package weathercli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testCurrentResponse = `{"latitude":52.52,"longitude":13.42,"timezone":"Europe/Berlin","current":{"time":"2024-01-12T14:00","temperature_2m":3.1}}`
+
+func TestCacheServesFreshEntries(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(testCurrentResponse))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL, CacheDir: t.TempDir()})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		weather, err := client.CurrentByCoords(ctx, 52.52, 13.41, nil)
+		if err != nil {
+			t.Fatalf("CurrentByCoords #%d failed: %v", i, err)
+		}
+		if weather.Temperature != 3.1 || weather.Stale {
+			t.Errorf("CurrentByCoords #%d = %.1f stale=%v, want 3.1 fresh", i, weather.Temperature, weather.Stale)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(testCurrentResponse))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	client := NewClient(Options{BaseURL: srv.URL, CacheDir: dir, CacheTTL: time.Nanosecond})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.CurrentByCoords(ctx, 52.52, 13.41, nil); err != nil {
+			t.Fatalf("CurrentByCoords #%d failed: %v", i, err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests = %d, want 2 after expiry", got)
+	}
+
+	// Offline mode serves the expired entry and labels it stale.
+	offline := NewClient(Options{BaseURL: srv.URL, CacheDir: dir, CacheTTL: time.Nanosecond, Offline: true})
+	weather, err := offline.CurrentByCoords(ctx, 52.52, 13.41, nil)
+	if err != nil {
+		t.Fatalf("offline CurrentByCoords failed: %v", err)
+	}
+	if !weather.Stale || weather.CachedAt == nil {
+		t.Errorf("offline result stale=%v cachedAt=%v, want stale with timestamp", weather.Stale, weather.CachedAt)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests = %d, want no request in offline mode", got)
+	}
+}
+
+func TestOfflineWithoutCache(t *testing.T) {
+	client := NewClient(Options{BaseURL: "http://127.0.0.1:0", CacheDir: t.TempDir(), Offline: true})
+
+	_, err := client.CurrentByCoords(context.Background(), 1, 2, nil)
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("error = %v, want ErrOffline", err)
+	}
+}
+
+func TestDefaultCacheDir(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", base)
+
+	dir, err := DefaultCacheDir()
+	if err != nil {
+		t.Fatalf("DefaultCacheDir failed: %v", err)
+	}
+	if want := filepath.Join(base, "weathercli"); dir != want {
+		t.Errorf("DefaultCacheDir = %q, want %q", dir, want)
+	}
+}
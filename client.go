@@ -3,39 +3,91 @@ package weathercli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 const (
-	defaultBaseURL    = "https://api.open-meteo.com/v1"
-	defaultGeoBaseURL = "https://geocoding-api.open-meteo.com/v1"
-	defaultTimeout    = 10 * time.Second
+	defaultBaseURL        = "https://api.open-meteo.com/v1"
+	defaultGeoBaseURL     = "https://geocoding-api.open-meteo.com/v1"
+	defaultArchiveBaseURL = "https://archive-api.open-meteo.com/v1"
+	defaultAirQualityURL  = "https://air-quality-api.open-meteo.com/v1"
+	defaultMarineBaseURL  = "https://marine-api.open-meteo.com/v1"
+	defaultEnsembleURL    = "https://ensemble-api.open-meteo.com/v1"
+	defaultTimeout        = 10 * time.Second
+)
+
+// Variables requested from the forecast API.
+const (
+	currentFields = "temperature_2m,relative_humidity_2m,apparent_temperature,precipitation,rain,snowfall,weather_code,cloud_cover,pressure_msl,surface_pressure,wind_speed_10m,wind_direction_10m,uv_index"
+	hourlyFields  = "temperature_2m,relative_humidity_2m,apparent_temperature,precipitation_probability,precipitation,rain,snowfall,weather_code,pressure_msl,cloud_cover,wind_speed_10m,wind_direction_10m,uv_index"
+	dailyFields   = "temperature_2m_max,temperature_2m_min,apparent_temperature_max,apparent_temperature_min,sunrise,sunset,uv_index_max,precipitation_sum,rain_sum,snowfall_sum,precipitation_probability_max,weather_code,wind_speed_10m_max,wind_direction_10m_dominant"
+)
+
+// Time formats used by Open-Meteo. Times are local, without a zone suffix.
+const (
+	timeLayout = "2006-01-02T15:04"
+	dateLayout = "2006-01-02"
 )
 
 // Client handles weather API requests.
 type Client struct {
-	baseURL    string
-	geoBaseURL string
-	httpClient *http.Client
+	baseURL           string
+	geoBaseURL        string
+	archiveBaseURL    string
+	airQualityBaseURL string
+	marineBaseURL     string
+	ensembleBaseURL   string
+	units             Units
+	httpClient        *http.Client
+	cache             *fileCache
+	cacheTTL          time.Duration
+	offline           bool
+	maxRetries        int
+	retry             RetryPolicy
 }
 
 // Options for creating a new client.
 type Options struct {
-	BaseURL    string
-	GeoBaseURL string
-	Timeout    time.Duration
+	BaseURL           string
+	GeoBaseURL        string
+	ArchiveBaseURL    string
+	AirQualityBaseURL string
+	MarineBaseURL     string
+	EnsembleBaseURL   string
+	Timeout           time.Duration
+	Units             Units // Defaults to MetricUnits()
+
+	// CacheDir enables the on-disk response cache (see DefaultCacheDir).
+	// Geocoding results are kept for 30 days, other responses for CacheTTL.
+	CacheDir string
+	CacheTTL time.Duration // Defaults to DefaultCacheTTL
+	Offline  bool          // Serve only cached responses, however old
+
+	// MaxRetries is how many times a request is retried after a network
+	// error, 429 or 5xx. Retries honor Retry-After and never outlast the
+	// request context's deadline. Zero disables retries.
+	MaxRetries  int
+	RetryPolicy RetryPolicy
 }
 
 // NewClient creates a new weather client.
 func NewClient(opts ...Options) *Client {
 	opt := Options{
-		BaseURL:    defaultBaseURL,
-		GeoBaseURL: defaultGeoBaseURL,
-		Timeout:    defaultTimeout,
+		BaseURL:           defaultBaseURL,
+		GeoBaseURL:        defaultGeoBaseURL,
+		ArchiveBaseURL:    defaultArchiveBaseURL,
+		AirQualityBaseURL: defaultAirQualityURL,
+		MarineBaseURL:     defaultMarineBaseURL,
+		EnsembleBaseURL:   defaultEnsembleURL,
+		Timeout:           defaultTimeout,
+		Units:             MetricUnits(),
+		CacheTTL:          DefaultCacheTTL,
 	}
 	if len(opts) > 0 {
 		if opts[0].BaseURL != "" {
@@ -44,85 +96,260 @@ func NewClient(opts ...Options) *Client {
 		if opts[0].GeoBaseURL != "" {
 			opt.GeoBaseURL = opts[0].GeoBaseURL
 		}
+		if opts[0].ArchiveBaseURL != "" {
+			opt.ArchiveBaseURL = opts[0].ArchiveBaseURL
+		}
+		if opts[0].AirQualityBaseURL != "" {
+			opt.AirQualityBaseURL = opts[0].AirQualityBaseURL
+		}
+		if opts[0].MarineBaseURL != "" {
+			opt.MarineBaseURL = opts[0].MarineBaseURL
+		}
+		if opts[0].EnsembleBaseURL != "" {
+			opt.EnsembleBaseURL = opts[0].EnsembleBaseURL
+		}
 		if opts[0].Timeout > 0 {
 			opt.Timeout = opts[0].Timeout
 		}
+		opt.Units = opts[0].Units.orDefault()
+		opt.CacheDir = opts[0].CacheDir
+		if opts[0].CacheTTL > 0 {
+			opt.CacheTTL = opts[0].CacheTTL
+		}
+		opt.Offline = opts[0].Offline
+		if opts[0].MaxRetries > 0 {
+			opt.MaxRetries = opts[0].MaxRetries
+		}
+		opt.RetryPolicy = opts[0].RetryPolicy
 	}
 
-	return &Client{
-		baseURL:    opt.BaseURL,
-		geoBaseURL: opt.GeoBaseURL,
-		httpClient: &http.Client{Timeout: opt.Timeout},
+	client := &Client{
+		baseURL:           opt.BaseURL,
+		geoBaseURL:        opt.GeoBaseURL,
+		archiveBaseURL:    opt.ArchiveBaseURL,
+		airQualityBaseURL: opt.AirQualityBaseURL,
+		marineBaseURL:     opt.MarineBaseURL,
+		ensembleBaseURL:   opt.EnsembleBaseURL,
+		units:             opt.Units,
+		httpClient:        &http.Client{Timeout: opt.Timeout},
+		cacheTTL:          opt.CacheTTL,
+		offline:           opt.Offline,
+		maxRetries:        opt.MaxRetries,
+		retry:             opt.RetryPolicy.withDefaults(),
+	}
+	if opt.CacheDir != "" {
+		client.cache = &fileCache{dir: opt.CacheDir}
 	}
+
+	return client
 }
 
-// SearchLocation finds locations by name.
-func (c *Client) SearchLocation(ctx context.Context, query string) ([]Location, error) {
-	u, err := url.Parse(c.geoBaseURL + "/search")
+// getJSON performs a GET request and decodes the JSON response into v.
+// api names the upstream service in error messages. When the cache is
+// enabled, fresh entries are served without a request; in offline mode any
+// cached entry is served and expired ones are reported as stale. Requests
+// made by a watch skip the cache lookup so each refresh sees new data.
+func (c *Client) getJSON(ctx context.Context, u *url.URL, api string, v interface{}) (responseMeta, error) {
+	rawURL := u.String()
+
+	if c.cache != nil && (!refreshing(ctx) || c.offline) {
+		ttl := c.cacheTTL
+		if api == "geocoding" {
+			ttl = geocodeCacheTTL
+		}
+		if entry, ok := c.cache.get(rawURL); ok {
+			age := time.Since(entry.FetchedAt)
+			if age < ttl || c.offline {
+				meta := responseMeta{stale: age >= ttl, fetchedAt: entry.FetchedAt}
+				if err := json.Unmarshal(entry.Body, v); err != nil {
+					return responseMeta{}, &DecodeError{Endpoint: api, Err: err}
+				}
+				return meta, nil
+			}
+		}
+	}
+
+	if c.offline {
+		return responseMeta{}, fmt.Errorf("%s: %w", api, ErrOffline)
+	}
+
+	body, err := c.fetch(ctx, rawURL, api)
 	if err != nil {
-		return nil, err
+		return responseMeta{}, err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return responseMeta{}, &DecodeError{Endpoint: api, Err: err}
 	}
 
-	q := u.Query()
-	q.Set("name", query)
-	q.Set("count", "10")
-	q.Set("language", "en")
-	q.Set("format", "json")
-	u.RawQuery = q.Encode()
+	meta := responseMeta{fetchedAt: time.Now()}
+	if c.cache != nil {
+		// A failed write only costs a future request.
+		_ = c.cache.put(rawURL, body, meta.fetchedAt)
+	}
+
+	return meta, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+// fetch GETs rawURL and returns the response body, retrying transient
+// failures according to the client's retry policy.
+func (c *Client) fetch(ctx context.Context, rawURL, api string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, transient, retryAfter, err := c.fetchOnce(ctx, rawURL, api)
+		if err == nil {
+			return body, nil
+		}
+		if !transient || attempt > c.maxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+
+		// A Retry-After beyond MaxDelay means the server is unavailable
+		// for longer than is worth waiting; give up rather than hang.
+		if retryAfter > c.retry.MaxDelay {
+			return nil, err
+		}
+		delay := c.retry.backoff(attempt)
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return nil, err
+		}
+		if c.retry.OnRetry != nil {
+			c.retry.OnRetry(attempt, delay, err)
+		}
+		if !sleep(ctx, delay) {
+			return nil, err
+		}
+	}
+}
+
+// fetchOnce performs a single GET. transient reports whether a failure is
+// worth retrying; retryAfter is the server's requested delay, if any.
+func (c *Client) fetchOnce(ctx context.Context, rawURL, api string) (body []byte, transient bool, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, 0, err
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, true, 0, &NetworkError{Endpoint: api, Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("geocoding API error: %d %s", resp.StatusCode, string(body))
+		err := newAPIError(api, resp.StatusCode, body)
+		return nil, retryableStatus(resp.StatusCode), parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), err
 	}
 
-	var result struct {
-		Results []struct {
-			Name      string  `json:"name"`
-			Latitude  float64 `json:"latitude"`
-			Longitude float64 `json:"longitude"`
-			Country   string  `json:"country"`
-			Admin1    string  `json:"admin1"`
-			Timezone  string  `json:"timezone"`
-		} `json:"results"`
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, 0, &NetworkError{Endpoint: api, Err: err}
 	}
+	return body, false, 0, nil
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+// SearchOptions narrow down location searches.
+type SearchOptions struct {
+	Count       int    // Max results (1-100); defaults to 10
+	CountryCode string // ISO 3166-1 alpha-2 code, e.g. "US"
+	Admin       string // State or region name matched against Admin1/Admin2, e.g. "Oregon"
+}
+
+// SearchLocation finds locations by name, best match first.
+func (c *Client) SearchLocation(ctx context.Context, query string, opts ...SearchOptions) ([]Location, error) {
+	var opt SearchOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Count <= 0 {
+		opt.Count = 10
+	}
+
+	u, err := url.Parse(c.geoBaseURL + "/search")
+	if err != nil {
 		return nil, err
 	}
 
-	if len(result.Results) == 0 {
-		return nil, fmt.Errorf("location not found: %s", query)
+	count := opt.Count
+	if opt.Admin != "" {
+		// The API cannot filter by region; fetch more and filter here.
+		count = 100
 	}
 
-	locations := make([]Location, len(result.Results))
-	for i, r := range result.Results {
-		locations[i] = Location{
-			Name:      r.Name,
-			Latitude:  r.Latitude,
-			Longitude: r.Longitude,
-			Country:   r.Country,
-			Admin1:    r.Admin1,
-			Timezone:  r.Timezone,
+	q := u.Query()
+	q.Set("name", query)
+	q.Set("count", fmt.Sprintf("%d", count))
+	q.Set("language", "en")
+	q.Set("format", "json")
+	if opt.CountryCode != "" {
+		q.Set("countryCode", strings.ToUpper(opt.CountryCode))
+	}
+	u.RawQuery = q.Encode()
+
+	var result struct {
+		Results []Location `json:"results"`
+	}
+
+	if _, err := c.getJSON(ctx, u, "geocoding", &result); err != nil {
+		return nil, err
+	}
+
+	locations := result.Results
+	if opt.Admin != "" {
+		filtered := locations[:0]
+		for _, loc := range locations {
+			if strings.EqualFold(loc.Admin1, opt.Admin) || strings.EqualFold(loc.Admin2, opt.Admin) {
+				filtered = append(filtered, loc)
+			}
 		}
+		locations = filtered
+	}
+	if len(locations) > opt.Count {
+		locations = locations[:opt.Count]
+	}
+
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrLocationNotFound, query)
 	}
 
 	return locations, nil
 }
 
-// Current fetches current weather for a location.
-func (c *Client) Current(ctx context.Context, location string) (*CurrentWeather, error) {
-	locations, err := c.SearchLocation(ctx, location)
+// LocationByID looks up a location by its GeoNames ID, as returned in
+// Location.ID.
+func (c *Client) LocationByID(ctx context.Context, id int64) (*Location, error) {
+	u, err := url.Parse(c.geoBaseURL + "/get")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("id", fmt.Sprintf("%d", id))
+	q.Set("language", "en")
+	q.Set("format", "json")
+	u.RawQuery = q.Encode()
+
+	var loc Location
+	if _, err := c.getJSON(ctx, u, "geocoding", &loc); err != nil {
+		// Unknown IDs are rejected as bad requests.
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusNotFound) {
+			return nil, fmt.Errorf("%w: id %d: %w", ErrLocationNotFound, id, err)
+		}
+		return nil, err
+	}
+	if loc.ID == 0 {
+		return nil, fmt.Errorf("%w: id %d", ErrLocationNotFound, id)
+	}
+
+	return &loc, nil
+}
+
+// Current fetches current weather for the best match of a location search.
+func (c *Client) Current(ctx context.Context, location string, opts ...SearchOptions) (*CurrentWeather, error) {
+	locations, err := c.SearchLocation(ctx, location, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -141,97 +368,92 @@ func (c *Client) CurrentByCoords(ctx context.Context, lat, lon float64, loc *Loc
 	q := u.Query()
 	q.Set("latitude", fmt.Sprintf("%.4f", lat))
 	q.Set("longitude", fmt.Sprintf("%.4f", lon))
-	q.Set("current", "temperature_2m,relative_humidity_2m,apparent_temperature,precipitation,rain,snowfall,weather_code,cloud_cover,pressure_msl,surface_pressure,wind_speed_10m,wind_direction_10m,uv_index")
+	q.Set("current", currentFields)
 	q.Set("timezone", "auto")
+	c.units.setQuery(q)
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	var result currentResponse
+	meta, err := c.getJSON(ctx, u, "weather", &result)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	weather, err := result.weather(loc, c.units)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("weather API error: %d %s", resp.StatusCode, string(body))
-	}
+	weather.Stale, weather.CachedAt = meta.staleSince()
 
-	var result struct {
-		Latitude  float64 `json:"latitude"`
-		Longitude float64 `json:"longitude"`
-		Timezone  string  `json:"timezone"`
-		Current   struct {
-			Time          string  `json:"time"`
-			Temperature   float64 `json:"temperature_2m"`
-			Apparent      float64 `json:"apparent_temperature"`
-			Humidity      int     `json:"relative_humidity_2m"`
-			Precipitation float64 `json:"precipitation"`
-			Rain          float64 `json:"rain"`
-			Snowfall      float64 `json:"snowfall"`
-			WeatherCode   int     `json:"weather_code"`
-			CloudCover    int     `json:"cloud_cover"`
-			Pressure      float64 `json:"pressure_msl"`
-			WindSpeed     float64 `json:"wind_speed_10m"`
-			WindDirection int     `json:"wind_direction_10m"`
-			UVIndex       float64 `json:"uv_index"`
-		} `json:"current"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
+	return weather, nil
+}
 
-	// Load timezone for parsing times
-	tz, err := time.LoadLocation(result.Timezone)
-	if err != nil {
-		tz = time.UTC
-	}
+// currentResponse is the forecast API response for current conditions.
+type currentResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+	Current   struct {
+		Time          string  `json:"time"`
+		Temperature   float64 `json:"temperature_2m"`
+		Apparent      float64 `json:"apparent_temperature"`
+		Humidity      int     `json:"relative_humidity_2m"`
+		Precipitation float64 `json:"precipitation"`
+		Rain          float64 `json:"rain"`
+		Snowfall      float64 `json:"snowfall"`
+		WeatherCode   int     `json:"weather_code"`
+		CloudCover    int     `json:"cloud_cover"`
+		Pressure      float64 `json:"pressure_msl"`
+		WindSpeed     float64 `json:"wind_speed_10m"`
+		WindDirection int     `json:"wind_direction_10m"`
+		UVIndex       float64 `json:"uv_index"`
+	} `json:"current"`
+}
 
+// weather converts the response. loc, if non-nil, is used as the location.
+func (r currentResponse) weather(loc *Location, units Units) (*CurrentWeather, error) {
 	// Parse time in format "2006-01-02T15:04"
-	t, err := time.ParseInLocation("2006-01-02T15:04", result.Current.Time, tz)
+	t, err := time.ParseInLocation(timeLayout, r.Current.Time, loadTimezone(r.Timezone))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse time %q: %w", result.Current.Time, err)
+		return nil, &DecodeError{Endpoint: "weather", Err: fmt.Errorf("failed to parse time %q: %w", r.Current.Time, err)}
 	}
 
 	weather := &CurrentWeather{
+		SchemaVersion: SchemaVersion,
+		Units:         units,
 		Time:          t,
-		Temperature:   result.Current.Temperature,
-		Apparent:      result.Current.Apparent,
-		Humidity:      result.Current.Humidity,
-		Precipitation: result.Current.Precipitation,
-		Rain:          result.Current.Rain,
-		Snowfall:      result.Current.Snowfall,
-		WindSpeed:     result.Current.WindSpeed,
-		WindDirection: result.Current.WindDirection,
-		Pressure:      result.Current.Pressure,
-		CloudCover:    result.Current.CloudCover,
-		UVIndex:       result.Current.UVIndex,
-		WeatherCode:   result.Current.WeatherCode,
-		Condition:     GetCondition(result.Current.WeatherCode),
+		Temperature:   r.Current.Temperature,
+		Apparent:      r.Current.Apparent,
+		Humidity:      r.Current.Humidity,
+		Precipitation: r.Current.Precipitation,
+		Rain:          r.Current.Rain,
+		Snowfall:      r.Current.Snowfall,
+		WindSpeed:     r.Current.WindSpeed,
+		WindDirection: r.Current.WindDirection,
+		Pressure:      r.Current.Pressure,
+		CloudCover:    r.Current.CloudCover,
+		UVIndex:       r.Current.UVIndex,
+		WeatherCode:   r.Current.WeatherCode,
+		Condition:     GetCondition(r.Current.WeatherCode),
 	}
 
 	if loc != nil {
 		weather.Location = *loc
-		weather.Location.Timezone = result.Timezone
+		weather.Location.Timezone = r.Timezone
 	} else {
 		weather.Location = Location{
-			Latitude:  result.Latitude,
-			Longitude: result.Longitude,
-			Timezone:  result.Timezone,
+			Latitude:  r.Latitude,
+			Longitude: r.Longitude,
+			Timezone:  r.Timezone,
 		}
 	}
 
 	return weather, nil
 }
 
-// Forecast fetches weather forecast for a location.
-func (c *Client) Forecast(ctx context.Context, location string, days int, hourly bool) (*Forecast, error) {
-	locations, err := c.SearchLocation(ctx, location)
+// Forecast fetches weather forecast for the best match of a location search.
+func (c *Client) Forecast(ctx context.Context, location string, days int, hourly bool, opts ...SearchOptions) (*Forecast, error) {
+	locations, err := c.SearchLocation(ctx, location, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -252,164 +474,185 @@ func (c *Client) ForecastByCoords(ctx context.Context, lat, lon float64, days in
 	q.Set("longitude", fmt.Sprintf("%.4f", lon))
 	q.Set("timezone", "auto")
 	q.Set("forecast_days", fmt.Sprintf("%d", days))
+	c.units.setQuery(q)
 
 	if hourly {
-		q.Set("hourly", "temperature_2m,relative_humidity_2m,apparent_temperature,precipitation_probability,precipitation,rain,snowfall,weather_code,pressure_msl,cloud_cover,wind_speed_10m,wind_direction_10m,uv_index")
+		q.Set("hourly", hourlyFields)
 	} else {
-		q.Set("daily", "temperature_2m_max,temperature_2m_min,apparent_temperature_max,apparent_temperature_min,sunrise,sunset,uv_index_max,precipitation_sum,rain_sum,snowfall_sum,precipitation_probability_max,weather_code,wind_speed_10m_max,wind_direction_10m_dominant")
+		q.Set("daily", dailyFields)
 	}
 
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	var result seriesResponse
+	meta, err := c.getJSON(ctx, u, "weather", &result)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	forecast, err := result.forecast("weather", lat, lon, loc, c.units)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	forecast.Stale, forecast.CachedAt = meta.staleSince()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("weather API error: %d %s", resp.StatusCode, string(body))
-	}
+	return forecast, nil
+}
+
+// seriesResponse is the hourly/daily payload shared by the forecast and
+// archive APIs. Variables an endpoint does not provide decode as empty
+// slices and map to zero values.
+type seriesResponse struct {
+	Timezone string `json:"timezone"`
+	Hourly   struct {
+		Time          []string  `json:"time"`
+		Temperature   []float64 `json:"temperature_2m"`
+		Apparent      []float64 `json:"apparent_temperature"`
+		Humidity      []int     `json:"relative_humidity_2m"`
+		PrecipProb    []int     `json:"precipitation_probability"`
+		Precipitation []float64 `json:"precipitation"`
+		Rain          []float64 `json:"rain"`
+		Snowfall      []float64 `json:"snowfall"`
+		WeatherCode   []int     `json:"weather_code"`
+		Pressure      []float64 `json:"pressure_msl"`
+		CloudCover    []int     `json:"cloud_cover"`
+		WindSpeed     []float64 `json:"wind_speed_10m"`
+		WindDirection []int     `json:"wind_direction_10m"`
+		UVIndex       []float64 `json:"uv_index"`
+	} `json:"hourly"`
+	Daily struct {
+		Time          []string  `json:"time"`
+		TempMax       []float64 `json:"temperature_2m_max"`
+		TempMin       []float64 `json:"temperature_2m_min"`
+		ApparentMax   []float64 `json:"apparent_temperature_max"`
+		ApparentMin   []float64 `json:"apparent_temperature_min"`
+		Sunrise       []string  `json:"sunrise"`
+		Sunset        []string  `json:"sunset"`
+		UVIndexMax    []float64 `json:"uv_index_max"`
+		Precipitation []float64 `json:"precipitation_sum"`
+		Rain          []float64 `json:"rain_sum"`
+		Snowfall      []float64 `json:"snowfall_sum"`
+		PrecipProb    []int     `json:"precipitation_probability_max"`
+		WeatherCode   []int     `json:"weather_code"`
+		WindSpeedMax  []float64 `json:"wind_speed_10m_max"`
+		WindDirection []int     `json:"wind_direction_10m_dominant"`
+	} `json:"daily"`
+}
 
-	forecast := &Forecast{}
+// forecast converts the response into a Forecast. api names the endpoint
+// the response came from, for errors.
+func (r *seriesResponse) forecast(api string, lat, lon float64, loc *Location, units Units) (*Forecast, error) {
+	forecast := &Forecast{SchemaVersion: SchemaVersion, Units: units}
 
 	if loc != nil {
 		forecast.Location = *loc
 	} else {
 		forecast.Location = Location{Latitude: lat, Longitude: lon}
 	}
+	if r.Timezone != "" {
+		forecast.Location.Timezone = r.Timezone
+	}
 
-	if hourly {
-		var result struct {
-			Timezone string `json:"timezone"`
-			Hourly   struct {
-				Time          []string  `json:"time"`
-				Temperature   []float64 `json:"temperature_2m"`
-				Apparent      []float64 `json:"apparent_temperature"`
-				Humidity      []int     `json:"relative_humidity_2m"`
-				PrecipProb    []int     `json:"precipitation_probability"`
-				Precipitation []float64 `json:"precipitation"`
-				Rain          []float64 `json:"rain"`
-				Snowfall      []float64 `json:"snowfall"`
-				WeatherCode   []int     `json:"weather_code"`
-				Pressure      []float64 `json:"pressure_msl"`
-				CloudCover    []int     `json:"cloud_cover"`
-				WindSpeed     []float64 `json:"wind_speed_10m"`
-				WindDirection []int     `json:"wind_direction_10m"`
-				UVIndex       []float64 `json:"uv_index"`
-			} `json:"hourly"`
-		}
-
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, err
-		}
-
-		// Load timezone for parsing times
-		tz, err := time.LoadLocation(result.Timezone)
-		if err != nil {
-			tz = time.UTC
-		}
+	// Load timezone for parsing times
+	tz := loadTimezone(r.Timezone)
 
-		forecast.Hourly = make([]HourlyForecast, len(result.Hourly.Time))
-		for i := range result.Hourly.Time {
-			t, err := time.ParseInLocation("2006-01-02T15:04", result.Hourly.Time[i], tz)
+	if len(r.Hourly.Time) > 0 {
+		h := r.Hourly
+		forecast.Hourly = make([]HourlyForecast, len(h.Time))
+		for i := range h.Time {
+			t, err := time.ParseInLocation(timeLayout, h.Time[i], tz)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse hourly time %q: %w", result.Hourly.Time[i], err)
+				return nil, &DecodeError{Endpoint: api, Err: fmt.Errorf("failed to parse hourly time %q: %w", h.Time[i], err)}
 			}
+			code := at(h.WeatherCode, i)
 			forecast.Hourly[i] = HourlyForecast{
 				Time:          t,
-				Temperature:   result.Hourly.Temperature[i],
-				Apparent:      result.Hourly.Apparent[i],
-				Humidity:      result.Hourly.Humidity[i],
-				PrecipProb:    result.Hourly.PrecipProb[i],
-				Precipitation: result.Hourly.Precipitation[i],
-				Rain:          result.Hourly.Rain[i],
-				Snowfall:      result.Hourly.Snowfall[i],
-				WeatherCode:   result.Hourly.WeatherCode[i],
-				Condition:     GetCondition(result.Hourly.WeatherCode[i]),
-				Pressure:      result.Hourly.Pressure[i],
-				CloudCover:    result.Hourly.CloudCover[i],
-				WindSpeed:     result.Hourly.WindSpeed[i],
-				WindDirection: result.Hourly.WindDirection[i],
-				UVIndex:       result.Hourly.UVIndex[i],
+				Temperature:   at(h.Temperature, i),
+				Apparent:      at(h.Apparent, i),
+				Humidity:      at(h.Humidity, i),
+				PrecipProb:    at(h.PrecipProb, i),
+				Precipitation: at(h.Precipitation, i),
+				Rain:          at(h.Rain, i),
+				Snowfall:      at(h.Snowfall, i),
+				WeatherCode:   code,
+				Condition:     GetCondition(code),
+				Pressure:      at(h.Pressure, i),
+				CloudCover:    at(h.CloudCover, i),
+				WindSpeed:     at(h.WindSpeed, i),
+				WindDirection: at(h.WindDirection, i),
+				UVIndex:       at(h.UVIndex, i),
 			}
 		}
-	} else {
-		var result struct {
-			Timezone string `json:"timezone"`
-			Daily    struct {
-				Time          []string  `json:"time"`
-				TempMax       []float64 `json:"temperature_2m_max"`
-				TempMin       []float64 `json:"temperature_2m_min"`
-				ApparentMax   []float64 `json:"apparent_temperature_max"`
-				ApparentMin   []float64 `json:"apparent_temperature_min"`
-				Sunrise       []string  `json:"sunrise"`
-				Sunset        []string  `json:"sunset"`
-				UVIndexMax    []float64 `json:"uv_index_max"`
-				Precipitation []float64 `json:"precipitation_sum"`
-				Rain          []float64 `json:"rain_sum"`
-				Snowfall      []float64 `json:"snowfall_sum"`
-				PrecipProb    []int     `json:"precipitation_probability_max"`
-				WeatherCode   []int     `json:"weather_code"`
-				WindSpeedMax  []float64 `json:"wind_speed_10m_max"`
-				WindDirection []int     `json:"wind_direction_10m_dominant"`
-			} `json:"daily"`
-		}
-
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, err
-		}
-
-		// Load timezone for parsing times
-		tz, err := time.LoadLocation(result.Timezone)
-		if err != nil {
-			tz = time.UTC
-		}
+	}
 
-		forecast.Daily = make([]DailyForecast, len(result.Daily.Time))
-		for i := range result.Daily.Time {
+	if len(r.Daily.Time) > 0 {
+		d := r.Daily
+		forecast.Daily = make([]DailyForecast, len(d.Time))
+		for i := range d.Time {
 			// Parse date (no timezone needed for date-only)
-			date, err := time.Parse("2006-01-02", result.Daily.Time[i])
+			date, err := time.Parse(dateLayout, d.Time[i])
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse date %q: %w", result.Daily.Time[i], err)
+				return nil, &DecodeError{Endpoint: api, Err: fmt.Errorf("failed to parse date %q: %w", d.Time[i], err)}
 			}
 
 			// Parse sunrise/sunset with timezone
-			sunrise, err := time.ParseInLocation("2006-01-02T15:04", result.Daily.Sunrise[i], tz)
+			sunrise, err := parseOptionalTime(at(d.Sunrise, i), tz)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse sunrise %q: %w", result.Daily.Sunrise[i], err)
+				return nil, &DecodeError{Endpoint: api, Err: fmt.Errorf("failed to parse sunrise %q: %w", at(d.Sunrise, i), err)}
 			}
-			sunset, err := time.ParseInLocation("2006-01-02T15:04", result.Daily.Sunset[i], tz)
+			sunset, err := parseOptionalTime(at(d.Sunset, i), tz)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse sunset %q: %w", result.Daily.Sunset[i], err)
+				return nil, &DecodeError{Endpoint: api, Err: fmt.Errorf("failed to parse sunset %q: %w", at(d.Sunset, i), err)}
 			}
 
+			code := at(d.WeatherCode, i)
 			forecast.Daily[i] = DailyForecast{
 				Date:          date,
-				TempMax:       result.Daily.TempMax[i],
-				TempMin:       result.Daily.TempMin[i],
-				ApparentMax:   result.Daily.ApparentMax[i],
-				ApparentMin:   result.Daily.ApparentMin[i],
+				TempMax:       at(d.TempMax, i),
+				TempMin:       at(d.TempMin, i),
+				ApparentMax:   at(d.ApparentMax, i),
+				ApparentMin:   at(d.ApparentMin, i),
 				Sunrise:       sunrise,
 				Sunset:        sunset,
-				UVIndexMax:    result.Daily.UVIndexMax[i],
-				Precipitation: result.Daily.Precipitation[i],
-				Rain:          result.Daily.Rain[i],
-				Snowfall:      result.Daily.Snowfall[i],
-				PrecipProb:    result.Daily.PrecipProb[i],
-				WeatherCode:   result.Daily.WeatherCode[i],
-				Condition:     GetCondition(result.Daily.WeatherCode[i]),
-				WindSpeedMax:  result.Daily.WindSpeedMax[i],
-				WindDirection: result.Daily.WindDirection[i],
+				UVIndexMax:    at(d.UVIndexMax, i),
+				Precipitation: at(d.Precipitation, i),
+				Rain:          at(d.Rain, i),
+				Snowfall:      at(d.Snowfall, i),
+				PrecipProb:    at(d.PrecipProb, i),
+				WeatherCode:   code,
+				Condition:     GetCondition(code),
+				WindSpeedMax:  at(d.WindSpeedMax, i),
+				WindDirection: at(d.WindDirection, i),
 			}
 		}
 	}
 
 	return forecast, nil
 }
+
+// loadTimezone returns the named timezone, falling back to UTC.
+func loadTimezone(name string) *time.Location {
+	tz, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return tz
+}
+
+// parseOptionalTime parses an Open-Meteo local time, returning the zero
+// time for empty values.
+func parseOptionalTime(s string, tz *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(timeLayout, s, tz)
+}
+
+// at returns s[i], or the zero value when the variable was not returned.
+func at[T any](s []T, i int) T {
+	if i < len(s) {
+		return s[i]
+	}
+	var zero T
+	return zero
+}
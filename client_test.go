@@ -2,6 +2,9 @@ package weathercli
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -374,3 +377,130 @@ func TestClientOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrentByCoordsUnits(t *testing.T) {
+	var query map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		_, _ = w.Write([]byte(`{"latitude":40.71,"longitude":-74.01,"timezone":"America/New_York","current":{"time":"2024-01-12T09:00","temperature_2m":41.2,"wind_speed_10m":8.5}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL, Units: ImperialUnits()})
+	weather, err := client.CurrentByCoords(context.Background(), 40.7128, -74.0060, nil)
+	if err != nil {
+		t.Fatalf("CurrentByCoords failed: %v", err)
+	}
+
+	want := map[string]string{
+		"temperature_unit":   "fahrenheit",
+		"wind_speed_unit":    "mph",
+		"precipitation_unit": "inch",
+	}
+	for k, v := range want {
+		if query[k] != v {
+			t.Errorf("query %s = %q, want %q", k, query[k], v)
+		}
+	}
+	if weather.Units != ImperialUnits() {
+		t.Errorf("Units = %+v, want imperial", weather.Units)
+	}
+	if weather.Temperature != 41.2 {
+		t.Errorf("Temperature = %v, want 41.2", weather.Temperature)
+	}
+}
+
+const testPortlandResponse = `{"results":[
+{"id":5746545,"name":"Portland","latitude":45.52,"longitude":-122.68,"elevation":15,"country_code":"US","country":"United States","admin1":"Oregon","admin2":"Multnomah","timezone":"America/Los_Angeles","population":652503,"postcodes":["97201","97202"]},
+{"id":4975802,"name":"Portland","latitude":43.66,"longitude":-70.26,"elevation":19,"country_code":"US","country":"United States","admin1":"Maine","admin2":"Cumberland","timezone":"America/New_York","population":66194}]}`
+
+func TestSearchLocationOptions(t *testing.T) {
+	var query map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		_, _ = w.Write([]byte(testPortlandResponse))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{GeoBaseURL: srv.URL})
+	ctx := context.Background()
+
+	locations, err := client.SearchLocation(ctx, "Portland", SearchOptions{CountryCode: "us"})
+	if err != nil {
+		t.Fatalf("SearchLocation failed: %v", err)
+	}
+	if query["countryCode"] != "US" || query["count"] != "10" {
+		t.Errorf("query = %v, want countryCode=US count=10", query)
+	}
+	if len(locations) != 2 {
+		t.Fatalf("got %d locations, want 2", len(locations))
+	}
+
+	got := locations[0]
+	if got.ID != 5746545 || got.CountryCode != "US" || got.Admin2 != "Multnomah" ||
+		got.Population != 652503 || got.Elevation != 15 || len(got.Postcodes) != 2 {
+		t.Errorf("location fields = %+v", got)
+	}
+
+	locations, err = client.SearchLocation(ctx, "Portland", SearchOptions{Admin: "maine"})
+	if err != nil {
+		t.Fatalf("SearchLocation with admin failed: %v", err)
+	}
+	if len(locations) != 1 || locations[0].Admin1 != "Maine" {
+		t.Errorf("admin filter = %+v, want Portland, Maine", locations)
+	}
+	if query["count"] != "100" {
+		t.Errorf("count = %s, want 100 when filtering by admin", query["count"])
+	}
+
+	if _, err := client.SearchLocation(ctx, "Portland", SearchOptions{Admin: "Texas"}); !errors.Is(err, ErrLocationNotFound) {
+		t.Errorf("error = %v, want ErrLocationNotFound", err)
+	}
+
+	locations, err = client.SearchLocation(ctx, "Portland", SearchOptions{Count: 1})
+	if err != nil || len(locations) != 1 {
+		t.Errorf("Count 1 = %d locations, %v; want 1", len(locations), err)
+	}
+}
+
+func TestLocationByID(t *testing.T) {
+	var path, id string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, id = r.URL.Path, r.URL.Query().Get("id")
+		switch id {
+		case "1":
+			_, _ = w.Write([]byte(`{}`))
+			return
+		case "2":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":true,"reason":"Location not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":2950159,"name":"Berlin","latitude":52.52437,"longitude":13.41053,"country_code":"DE","country":"Germany","admin1":"Land Berlin","timezone":"Europe/Berlin","population":3426354}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{GeoBaseURL: srv.URL})
+	loc, err := client.LocationByID(context.Background(), 2950159)
+	if err != nil {
+		t.Fatalf("LocationByID failed: %v", err)
+	}
+	if path != "/get" {
+		t.Errorf("path = %s, want /get", path)
+	}
+	if loc.Name != "Berlin" || loc.CountryCode != "DE" || loc.Timezone != "Europe/Berlin" {
+		t.Errorf("location = %+v", loc)
+	}
+
+	for _, id := range []int64{1, 2} {
+		if _, err := client.LocationByID(context.Background(), id); !errors.Is(err, ErrLocationNotFound) {
+			t.Errorf("unknown id %d error = %v, want ErrLocationNotFound", id, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package weathercli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultConfigPath returns the config file path: $WEATHER_CONFIG if set,
+// otherwise config.toml in DefaultConfigDir.
+func DefaultConfigPath() (string, error) {
+	if path := os.Getenv("WEATHER_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := DefaultConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Config is a flat TOML configuration: top-level "key = value" pairs and
+// "[section]" tables of them. Keys are addressed as "key" or
+// "section.key"; dashes and underscores in key names are equivalent.
+// Only scalar values (strings, numbers, booleans) are supported. Set keeps
+// comments and layout intact.
+type Config struct {
+	lines  []string
+	values map[string]string // decoded values by normalized key
+	index  map[string]int    // line of each key
+}
+
+// NewConfig returns an empty Config.
+func NewConfig() *Config {
+	return &Config{values: map[string]string{}, index: map[string]int{}}
+}
+
+// LoadConfig reads the config file at path. A missing file is an empty
+// config.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewConfig(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ParseConfig(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig parses config file contents.
+func ParseConfig(data string) (*Config, error) {
+	cfg := NewConfig()
+	data = strings.TrimRight(data, "\n")
+	if data == "" {
+		return cfg, nil
+	}
+	section := ""
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		cfg.lines = append(cfg.lines, line)
+
+		text := strings.TrimSpace(line)
+		switch {
+		case text == "" || strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, "["):
+			end := strings.Index(text, "]")
+			if end < 0 || strings.TrimSpace(stripComment(text[end+1:])) != "" {
+				return nil, fmt.Errorf("line %d: invalid section header %q", i+1, text)
+			}
+			section = normalizeConfigKey(strings.TrimSpace(text[1:end]))
+			if section == "" {
+				return nil, fmt.Errorf("line %d: empty section name", i+1)
+			}
+			continue
+		}
+
+		key, raw, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key = normalizeConfigKey(strings.TrimSpace(key))
+		if key == "" || strings.ContainsAny(key, " \t\"'") {
+			return nil, fmt.Errorf("line %d: invalid key", i+1)
+		}
+		value, err := decodeConfigValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+		if _, dup := cfg.values[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", i+1, key)
+		}
+		cfg.values[key] = value
+		cfg.index[key] = len(cfg.lines) - 1
+	}
+	return cfg, nil
+}
+
+// Get returns the value of key ("key" or "section.key").
+func (c *Config) Get(key string) (string, bool) {
+	v, ok := c.values[normalizeConfigKey(key)]
+	return v, ok
+}
+
+// Keys returns all keys, sorted.
+func (c *Config) Keys() []string {
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Set sets key ("key" or "section.key") to value, replacing an existing
+// line or adding one to the right section.
+func (c *Config) Set(key, value string) {
+	key = normalizeConfigKey(key)
+	section, name := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		section, name = key[:i], key[i+1:]
+	}
+	line := name + " = " + encodeConfigValue(value)
+
+	if i, ok := c.index[key]; ok {
+		c.lines[i] = line
+		c.values[key] = value
+		return
+	}
+
+	// Insert after the last key of the section, or after its header, or
+	// (for top-level keys) before the first section.
+	pos := -1
+	current := ""
+	topEnd := len(c.lines) // end of the top-level part
+	for i, l := range c.lines {
+		text := strings.TrimSpace(l)
+		if strings.HasPrefix(text, "[") {
+			if section == "" {
+				topEnd = i
+				break
+			}
+			end := strings.Index(text, "]")
+			current = normalizeConfigKey(strings.TrimSpace(text[1:end]))
+			if current == section {
+				pos = i
+			}
+			continue
+		}
+		if current == section && text != "" && !strings.HasPrefix(text, "#") {
+			pos = i
+		}
+	}
+
+	if pos < 0 && section == "" {
+		pos = topEnd - 1
+	}
+	if pos < 0 && section != "" {
+		if len(c.lines) > 0 && strings.TrimSpace(c.lines[len(c.lines)-1]) != "" {
+			c.lines = append(c.lines, "")
+		}
+		c.lines = append(c.lines, "["+section+"]")
+		pos = len(c.lines) - 1
+	}
+	c.insertLine(pos+1, line)
+	c.values[key] = value
+	c.index[key] = pos + 1
+}
+
+func (c *Config) insertLine(at int, line string) {
+	c.lines = append(c.lines, "")
+	copy(c.lines[at+1:], c.lines[at:])
+	c.lines[at] = line
+	for k, i := range c.index {
+		if i >= at {
+			c.index[k] = i + 1
+		}
+	}
+}
+
+// Save writes the config to path, creating its directory. The file is
+// replaced atomically, so an interrupted save never leaves it truncated;
+// like saved locations, it is readable only by the user.
+func (c *Config) Save(path string) error {
+	return writeFileAtomic(path, []byte(strings.Join(c.lines, "\n")+"\n"))
+}
+
+// normalizeConfigKey makes "base-url" and "Base_URL" the same key.
+func normalizeConfigKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+}
+
+// decodeConfigValue decodes a TOML scalar: a basic or literal string,
+// number or boolean, optionally followed by a comment.
+func decodeConfigValue(raw string) (string, error) {
+	if raw == "" {
+		return "", fmt.Errorf("missing value")
+	}
+
+	switch raw[0] {
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(raw); i++ {
+			switch ch := raw[i]; ch {
+			case '\\':
+				if i+1 >= len(raw) {
+					return "", fmt.Errorf("unterminated string")
+				}
+				i++
+				switch raw[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case '"', '\\':
+					b.WriteByte(raw[i])
+				default:
+					return "", fmt.Errorf("unsupported escape \\%c", raw[i])
+				}
+			case '"':
+				if strings.TrimSpace(stripComment(raw[i+1:])) != "" {
+					return "", fmt.Errorf("unexpected text after string")
+				}
+				return b.String(), nil
+			default:
+				b.WriteByte(ch)
+			}
+		}
+		return "", fmt.Errorf("unterminated string")
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		if strings.TrimSpace(stripComment(raw[end+2:])) != "" {
+			return "", fmt.Errorf("unexpected text after string")
+		}
+		return raw[1 : end+1], nil
+	case '[', '{':
+		return "", fmt.Errorf("arrays and tables are not supported")
+	}
+
+	value := strings.TrimSpace(stripComment(raw))
+	if value == "true" || value == "false" {
+		return value, nil
+	}
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(value, "_", ""), 64); err == nil {
+		return strings.ReplaceAll(value, "_", ""), nil
+	}
+	return "", fmt.Errorf("invalid value %q (quote strings)", value)
+}
+
+// encodeConfigValue writes booleans and numbers bare and quotes the rest.
+func encodeConfigValue(value string) string {
+	if value == "true" || value == "false" {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "xXnN") {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func stripComment(s string) string {
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
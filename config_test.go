@@ -0,0 +1,153 @@
+package weathercli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `# weathercli settings
+location = "@home"
+units = 'imperial'   # °F, mph, inches
+timeout = "5s"
+no-color = true
+
+[forecast]
+days = 3
+hours = 1_000
+`
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig(testConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	want := map[string]string{
+		"location":       "@home",
+		"units":          "imperial",
+		"timeout":        "5s",
+		"no_color":       "true",
+		"no-color":       "true",
+		"forecast.days":  "3",
+		"forecast.hours": "1000",
+	}
+	for key, v := range want {
+		if got, ok := cfg.Get(key); !ok || got != v {
+			t.Errorf("Get(%q) = %q, %v; want %q", key, got, ok, v)
+		}
+	}
+	if _, ok := cfg.Get("days"); ok {
+		t.Error("section key leaked to top level")
+	}
+	if keys := cfg.Keys(); len(keys) != 6 {
+		t.Errorf("Keys() = %v, want 6 keys", keys)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []string{
+		"units = imperial",
+		"units",
+		`units = "imperial`,
+		`units = "a" "b"`,
+		"rules = [1, 2]",
+		"[forecast",
+		"days = 1\ndays = 2",
+	}
+	for _, input := range tests {
+		if _, err := ParseConfig(input); err == nil {
+			t.Errorf("ParseConfig(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestConfigSetKeepsLayout(t *testing.T) {
+	cfg, err := ParseConfig(testConfig)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	cfg.Set("units", "metric")
+	cfg.Set("cache_ttl", "1h")
+	cfg.Set("forecast.hourly", "true")
+	cfg.Set("alert.days", "5")
+
+	path := filepath.Join(t.TempDir(), "weathercli", "config.toml")
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# weathercli settings
+location = "@home"
+units = "metric"
+timeout = "5s"
+no-color = true
+cache_ttl = "1h"
+
+[forecast]
+days = 3
+hours = 1_000
+hourly = true
+
+[alert]
+days = 5
+`
+	if string(data) != want {
+		t.Errorf("saved config:\n%s\nwant:\n%s", data, want)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("config directory has %d entries, want only config.toml", len(entries))
+	}
+
+	reloaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	for _, key := range []string{"units", "cache_ttl", "forecast.hourly", "alert.days", "forecast.hours"} {
+		a, _ := cfg.Get(key)
+		b, _ := reloaded.Get(key)
+		if a != b {
+			t.Errorf("%s = %q after reload, want %q", key, b, a)
+		}
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "config.toml"))
+	if err != nil {
+		t.Fatalf("LoadConfig on missing file: %v", err)
+	}
+	if len(cfg.Keys()) != 0 {
+		t.Errorf("Keys() = %v, want none", cfg.Keys())
+	}
+
+	cfg.Set("units", "imperial")
+	if got := strings.Join(cfg.lines, "\n"); got != `units = "imperial"` {
+		t.Errorf("lines = %q", got)
+	}
+}
+
+func TestDefaultConfigPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("WEATHER_CONFIG", "")
+
+	got, err := DefaultConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "weathercli", "config.toml"); got != want {
+		t.Errorf("DefaultConfigPath() = %q, want %q", got, want)
+	}
+
+	t.Setenv("WEATHER_CONFIG", "/etc/weathercli.toml")
+	if got, _ := DefaultConfigPath(); got != "/etc/weathercli.toml" {
+		t.Errorf("DefaultConfigPath() with WEATHER_CONFIG = %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package weathercli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrNotCoordinates is returned by ParseCoordinates for input that is not
+// written as coordinates, such as a place name.
+var ErrNotCoordinates = errors.New("not coordinates")
+
+var (
+	decimalCoordsPattern = regexp.MustCompile(`^([+-]?[0-9]+(?:\.[0-9]+)?)\s*[,\s]\s*([+-]?[0-9]+(?:\.[0-9]+)?)$`)
+	dmsPattern           = `([0-9]+(?:\.[0-9]+)?)\s*°\s*(?:([0-9]+(?:\.[0-9]+)?)\s*['′]\s*)?(?:([0-9]+(?:\.[0-9]+)?)\s*(?:"|″|'')\s*)?([NSEW])`
+	dmsCoordsPattern     = regexp.MustCompile(`^` + dmsPattern + `\s*,?\s*` + dmsPattern + `$`)
+)
+
+// ParseCoordinates parses "lat,lon" in decimal degrees (e.g. "52.52,13.41")
+// or degrees-minutes-seconds (e.g. `52°31'N 13°24'E`). It returns
+// ErrNotCoordinates when s is not written as coordinates.
+func ParseCoordinates(s string) (lat, lon float64, err error) {
+	s = strings.TrimSpace(s)
+
+	if m := decimalCoordsPattern.FindStringSubmatch(s); m != nil {
+		lat, _ = strconv.ParseFloat(m[1], 64)
+		lon, _ = strconv.ParseFloat(m[2], 64)
+		return lat, lon, checkCoordinates(lat, lon)
+	}
+
+	m := dmsCoordsPattern.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil {
+		return 0, 0, ErrNotCoordinates
+	}
+
+	a, aHemi := dmsValue(m[1:5])
+	b, bHemi := dmsValue(m[5:9])
+	latFirst := aHemi == 'N' || aHemi == 'S'
+	if latFirst == (bHemi == 'N' || bHemi == 'S') {
+		return 0, 0, fmt.Errorf("invalid coordinates %q: need one of N/S and one of E/W", s)
+	}
+	if latFirst {
+		lat, lon = a, b
+	} else {
+		lat, lon = b, a
+	}
+	return lat, lon, checkCoordinates(lat, lon)
+}
+
+// dmsValue converts degrees, minutes, seconds and hemisphere submatches to
+// signed decimal degrees.
+func dmsValue(m []string) (float64, byte) {
+	deg, _ := strconv.ParseFloat(m[0], 64)
+	mins, _ := strconv.ParseFloat(m[1], 64)
+	secs, _ := strconv.ParseFloat(m[2], 64)
+	v := deg + mins/60 + secs/3600
+	hemi := m[3][0]
+	if hemi == 'S' || hemi == 'W' {
+		v = -v
+	}
+	return v, hemi
+}
+
+func checkCoordinates(lat, lon float64) error {
+	if lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %g out of range (-90 to 90)", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude %g out of range (-180 to 180)", lon)
+	}
+	return nil
+}
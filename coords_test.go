@@ -0,0 +1,53 @@
+package weathercli
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		input    string
+		lat, lon float64
+	}{
+		{"52.52,13.41", 52.52, 13.41},
+		{"52.52, 13.41", 52.52, 13.41},
+		{" -33.87 151.21 ", -33.87, 151.21},
+		{"+40.7128,-74.006", 40.7128, -74.006},
+		{"52°31'N 13°24'E", 52 + 31.0/60, 13 + 24.0/60},
+		{`52°31'12"N, 13°24'36"E`, 52 + 31.0/60 + 12.0/3600, 13 + 24.0/60 + 36.0/3600},
+		{"33°52′S 151°12′E", -(33 + 52.0/60), 151 + 12.0/60},
+		{"74°0'W 40°42'N", 40 + 42.0/60, -74},
+		{"52°n 13°e", 52, 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			lat, lon, err := ParseCoordinates(tt.input)
+			if err != nil {
+				t.Fatalf("ParseCoordinates(%q) error: %v", tt.input, err)
+			}
+			if math.Abs(lat-tt.lat) > 1e-9 || math.Abs(lon-tt.lon) > 1e-9 {
+				t.Errorf("ParseCoordinates(%q) = %v, %v; want %v, %v", tt.input, lat, lon, tt.lat, tt.lon)
+			}
+		})
+	}
+}
+
+func TestParseCoordinatesErrors(t *testing.T) {
+	notCoords := []string{"Berlin", "London, UK", "52.52", "id:2950159", "1 2 3", ""}
+	for _, input := range notCoords {
+		if _, _, err := ParseCoordinates(input); !errors.Is(err, ErrNotCoordinates) {
+			t.Errorf("ParseCoordinates(%q) error = %v, want ErrNotCoordinates", input, err)
+		}
+	}
+
+	invalid := []string{"91,0", "0,181", "52°N 13°S", "95°N 13°E"}
+	for _, input := range invalid {
+		_, _, err := ParseCoordinates(input)
+		if err == nil || errors.Is(err, ErrNotCoordinates) {
+			t.Errorf("ParseCoordinates(%q) error = %v, want range or hemisphere error", input, err)
+		}
+	}
+}
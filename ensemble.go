@@ -0,0 +1,271 @@
+package weathercli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Variables requested from the ensemble API. Each comes back once per
+// member, e.g. temperature_2m, temperature_2m_member01, ...
+var ensembleFields = []string{"temperature_2m", "precipitation", "wind_speed_10m"}
+
+// DefaultEnsembleModels are the models Ensemble uses when none are given.
+var DefaultEnsembleModels = []string{"icon_seamless"}
+
+// EnsembleOptions configures Ensemble.
+type EnsembleOptions struct {
+	// Models whose members are pooled, e.g. icon_seamless, gfs_seamless or
+	// ecmwf_ifs025. Defaults to DefaultEnsembleModels.
+	Models []string
+
+	// PrecipThreshold is the precipitation a member must exceed to count
+	// as wet: per hour for hourly forecasts, per day for daily ones. In
+	// the client's units; defaults to 0.1 mm (0.01 inch).
+	PrecipThreshold float64
+}
+
+// Ensemble fetches an ensemble forecast by coordinates and summarizes its
+// members for each day or hour: mean, min/max and 10th/90th percentiles,
+// plus the share of members with precipitation above the threshold. Daily
+// values are computed per member from its hours, so a member only counts
+// for days it fully covers.
+func (c *Client) Ensemble(ctx context.Context, lat, lon float64, days int, hourly bool, loc *Location, opts ...EnsembleOptions) (*EnsembleForecast, error) {
+	var opt EnsembleOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if len(opt.Models) == 0 {
+		opt.Models = DefaultEnsembleModels
+	}
+	if opt.PrecipThreshold == 0 {
+		opt.PrecipThreshold = 0.1
+		if c.units.Precipitation == Inches {
+			opt.PrecipThreshold = 0.01
+		}
+	}
+
+	u, err := url.Parse(c.ensembleBaseURL + "/ensemble")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("latitude", fmt.Sprintf("%.4f", lat))
+	q.Set("longitude", fmt.Sprintf("%.4f", lon))
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", fmt.Sprintf("%d", days))
+	q.Set("hourly", strings.Join(ensembleFields, ","))
+	q.Set("models", strings.Join(opt.Models, ","))
+	c.units.setQuery(q)
+
+	u.RawQuery = q.Encode()
+
+	var result struct {
+		Latitude  float64                    `json:"latitude"`
+		Longitude float64                    `json:"longitude"`
+		Timezone  string                     `json:"timezone"`
+		Hourly    map[string]json.RawMessage `json:"hourly"`
+	}
+
+	meta, err := c.getJSON(ctx, u, "ensemble", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	var times []string
+	if err := json.Unmarshal(result.Hourly["time"], &times); err != nil {
+		return nil, &DecodeError{Endpoint: "ensemble", Err: fmt.Errorf("hourly time: %w", err)}
+	}
+	members := map[string][][]*float64{}
+	for _, field := range ensembleFields {
+		if members[field], err = ensembleMembers(result.Hourly, field); err != nil {
+			return nil, &DecodeError{Endpoint: "ensemble", Err: err}
+		}
+	}
+	if len(members["temperature_2m"]) == 0 {
+		return nil, &DecodeError{Endpoint: "ensemble", Err: errors.New("no ensemble members in response")}
+	}
+
+	ensemble := &EnsembleForecast{
+		SchemaVersion:   SchemaVersion,
+		Units:           c.units,
+		Models:          opt.Models,
+		Members:         len(members["temperature_2m"]),
+		PrecipThreshold: opt.PrecipThreshold,
+	}
+	ensemble.Stale, ensemble.CachedAt = meta.staleSince()
+
+	if loc != nil {
+		ensemble.Location = *loc
+		ensemble.Location.Timezone = result.Timezone
+	} else {
+		ensemble.Location = Location{
+			Latitude:  result.Latitude,
+			Longitude: result.Longitude,
+			Timezone:  result.Timezone,
+		}
+	}
+
+	tz := loadTimezone(result.Timezone)
+	temp, precip, wind := members["temperature_2m"], members["precipitation"], members["wind_speed_10m"]
+
+	if hourly {
+		for i := range times {
+			t, err := time.ParseInLocation(timeLayout, times[i], tz)
+			if err != nil {
+				return nil, &DecodeError{Endpoint: "ensemble", Err: fmt.Errorf("failed to parse hourly time %q: %w", times[i], err)}
+			}
+			temps := memberValues(temp, i, i+1, nil)
+			if len(temps) == 0 {
+				continue // past the horizon of every model
+			}
+			amounts := memberValues(precip, i, i+1, nil)
+			ensemble.Hourly = append(ensemble.Hourly, EnsembleHour{
+				Time:          t,
+				Temperature:   newEnsembleStats(temps),
+				Precipitation: newEnsembleStats(amounts),
+				WindSpeed:     newEnsembleStats(memberValues(wind, i, i+1, nil)),
+				PrecipProb:    shareAbove(amounts, opt.PrecipThreshold),
+			})
+		}
+		return ensemble, nil
+	}
+
+	for start := 0; start < len(times); {
+		if len(times[start]) < len(dateLayout) {
+			return nil, &DecodeError{Endpoint: "ensemble", Err: fmt.Errorf("failed to parse hourly time %q", times[start])}
+		}
+		day := times[start][:len(dateLayout)]
+		end := start
+		for end < len(times) && strings.HasPrefix(times[end], day) {
+			end++
+		}
+		date, err := time.Parse(dateLayout, day)
+		if err != nil {
+			return nil, &DecodeError{Endpoint: "ensemble", Err: fmt.Errorf("failed to parse date %q: %w", day, err)}
+		}
+
+		maxima := memberValues(temp, start, end, slices.Max[[]float64])
+		if len(maxima) > 0 {
+			totals := memberValues(precip, start, end, func(hours []float64) float64 { return round2(sum(hours)) })
+			ensemble.Daily = append(ensemble.Daily, EnsembleDay{
+				Date:          date,
+				TempMax:       newEnsembleStats(maxima),
+				TempMin:       newEnsembleStats(memberValues(temp, start, end, slices.Min[[]float64])),
+				Precipitation: newEnsembleStats(totals),
+				WindSpeedMax:  newEnsembleStats(memberValues(wind, start, end, slices.Max[[]float64])),
+				PrecipProb:    shareAbove(totals, opt.PrecipThreshold),
+			})
+		}
+		start = end
+	}
+
+	return ensemble, nil
+}
+
+// ensembleMembers returns the series of every member of field, in key
+// order. Keys are the field name, optionally followed by _memberNN and,
+// when several models are requested, the model name.
+func ensembleMembers(hourly map[string]json.RawMessage, field string) ([][]*float64, error) {
+	var keys []string
+	for key := range hourly {
+		if key == field || strings.HasPrefix(key, field+"_") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var members [][]*float64
+	for _, key := range keys {
+		var series []*float64
+		if err := json.Unmarshal(hourly[key], &series); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		members = append(members, series)
+	}
+	return members, nil
+}
+
+// memberValues returns one value per member for hours [start, end):
+// aggregate of them, or the value at start when aggregate is nil. Members
+// missing any of the hours are left out.
+func memberValues(members [][]*float64, start, end int, aggregate func([]float64) float64) []float64 {
+	var out []float64
+	for _, series := range members {
+		var hours []float64
+		for i := start; i < end; i++ {
+			if i >= len(series) || series[i] == nil {
+				hours = nil
+				break
+			}
+			hours = append(hours, *series[i])
+		}
+		if len(hours) == 0 {
+			continue
+		}
+		if aggregate == nil {
+			out = append(out, hours[0])
+		} else {
+			out = append(out, aggregate(hours))
+		}
+	}
+	return out
+}
+
+func newEnsembleStats(values []float64) EnsembleStats {
+	if len(values) == 0 {
+		return EnsembleStats{}
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return EnsembleStats{
+		Mean: round2(sum(sorted) / float64(len(sorted))),
+		Min:  round2(sorted[0]),
+		P10:  round2(percentile(sorted, 0.1)),
+		P90:  round2(percentile(sorted, 0.9)),
+		Max:  round2(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile interpolates linearly between the closest ranks of sorted.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	if lo+1 >= len(sorted) {
+		return sorted[lo]
+	}
+	return sorted[lo] + (rank-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+// shareAbove returns the percentage of values above threshold.
+func shareAbove(values []float64, threshold float64) int {
+	if len(values) == 0 {
+		return 0
+	}
+	n := 0
+	for _, v := range values {
+		if v > threshold {
+			n++
+		}
+	}
+	return int(math.Round(100 * float64(n) / float64(len(values))))
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
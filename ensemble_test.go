@@ -0,0 +1,99 @@
+package weathercli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnsemble(t *testing.T) {
+	var models string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		models = r.URL.Query().Get("models")
+		_, _ = w.Write([]byte(`{"latitude":52.52,"longitude":13.41,"timezone":"Europe/Berlin","hourly":{
+			"time":["2024-06-01T00:00","2024-06-01T01:00","2024-06-02T00:00","2024-06-02T01:00"],
+			"temperature_2m_icon_seamless":[10,12,14,16],
+			"temperature_2m_member01_icon_seamless":[11,13,15,17],
+			"temperature_2m_gfs_seamless":[9,15,null,null],
+			"precipitation_icon_seamless":[0,0.2,0,0],
+			"precipitation_member01_icon_seamless":[0,0,1,1],
+			"precipitation_gfs_seamless":[0.5,0.5,null,null],
+			"wind_speed_10m_icon_seamless":[5,6,7,8],
+			"wind_speed_10m_member01_icon_seamless":[5,6,7,8],
+			"wind_speed_10m_gfs_seamless":[4,4,null,null]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{EnsembleBaseURL: srv.URL})
+	opts := EnsembleOptions{Models: []string{"icon_seamless", "gfs_seamless"}}
+	ensemble, err := client.Ensemble(context.Background(), 52.52, 13.41, 2, false, nil, opts)
+	if err != nil {
+		t.Fatalf("Ensemble failed: %v", err)
+	}
+
+	if models != "icon_seamless,gfs_seamless" {
+		t.Errorf("models = %q", models)
+	}
+	if ensemble.Members != 3 || ensemble.PrecipThreshold != 0.1 {
+		t.Errorf("Members = %d, PrecipThreshold = %v, want 3 and 0.1", ensemble.Members, ensemble.PrecipThreshold)
+	}
+	if len(ensemble.Daily) != 2 {
+		t.Fatalf("Daily = %+v, want 2 days", ensemble.Daily)
+	}
+	day := ensemble.Daily[0]
+	if want := (EnsembleStats{Mean: 13.33, Min: 12, P10: 12.2, P90: 14.6, Max: 15}); day.TempMax != want {
+		t.Errorf("TempMax = %+v, want %+v", day.TempMax, want)
+	}
+	if day.PrecipProb != 67 || day.Precipitation.Max != 1 {
+		t.Errorf("day 1 precipitation = %+v, %d%%", day.Precipitation, day.PrecipProb)
+	}
+	// The second model does not cover the second day.
+	day = ensemble.Daily[1]
+	if day.TempMax.Min != 16 || day.TempMax.Max != 17 || day.PrecipProb != 50 {
+		t.Errorf("day 2 = %+v", day)
+	}
+
+	ensemble, err = client.Ensemble(context.Background(), 52.52, 13.41, 2, true, nil, opts)
+	if err != nil {
+		t.Fatalf("Ensemble hourly failed: %v", err)
+	}
+	if len(ensemble.Hourly) != 4 {
+		t.Fatalf("Hourly = %+v, want 4 hours", ensemble.Hourly)
+	}
+	hour := ensemble.Hourly[0]
+	if hour.Temperature.Mean != 10 || hour.PrecipProb != 33 || hour.WindSpeed.Min != 4 {
+		t.Errorf("hour 1 = %+v", hour)
+	}
+}
+
+func TestEnsembleBadTime(t *testing.T) {
+	for _, times := range []string{`["2024"]`, `["2024-0x-01T00:00"]`} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"timezone":"UTC","hourly":{"time":` + times + `,"temperature_2m":[10]}}`))
+		}))
+		client := NewClient(Options{EnsembleBaseURL: srv.URL})
+		for _, hourly := range []bool{false, true} {
+			_, err := client.Ensemble(context.Background(), 0, 0, 1, hourly, nil)
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) || decodeErr.Endpoint != "ensemble" {
+				t.Errorf("time %s, hourly %v: error = %v, want ensemble *DecodeError", times, hourly, err)
+			}
+		}
+		srv.Close()
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+	if got := percentile(sorted, 0.1); got != 2 {
+		t.Errorf("p10 = %v, want 2", got)
+	}
+	if got := percentile(sorted, 0.9); got != 10 {
+		t.Errorf("p90 = %v, want 10", got)
+	}
+	if got := percentile([]float64{5}, 0.9); got != 5 {
+		t.Errorf("p90 of one value = %v, want 5", got)
+	}
+}
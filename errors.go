@@ -0,0 +1,142 @@
+package weathercli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrLocationNotFound is returned when geocoding finds no match for a query.
+var ErrLocationNotFound = errors.New("location not found")
+
+// APIError is returned when an Open-Meteo API answers with a non-200 status.
+type APIError struct {
+	StatusCode int
+	Endpoint   string // "weather", "geocoding", "archive", "air quality", "marine" or "ensemble"
+	Reason     string // Open-Meteo's error reason, or the status text
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error: %d %s", e.Endpoint, e.StatusCode, e.Reason)
+}
+
+// RateLimited reports whether the API rejected the request with HTTP 429.
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// newAPIError builds an APIError from an error response, using the
+// {"error":true,"reason":"..."} body Open-Meteo sends when present.
+func newAPIError(endpoint string, status int, body []byte) *APIError {
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	reason := ""
+	if json.Unmarshal(body, &payload) == nil {
+		reason = strings.TrimSpace(payload.Reason)
+	}
+	if reason == "" {
+		reason = http.StatusText(status)
+	}
+	return &APIError{StatusCode: status, Endpoint: endpoint, Reason: reason}
+}
+
+// NetworkError wraps a failure to reach an API or read its response.
+type NetworkError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%s API request failed: %v", e.Endpoint, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError wraps a response body that could not be decoded.
+type DecodeError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s API returned an invalid response: %v", e.Endpoint, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// UsageError marks an invalid request: bad flags, arguments or query
+// parameters. It is reported as ErrorCodeInvalidRequest.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// Error codes of ErrorDetail. They are stable: scripts may match on them.
+const (
+	ErrorCodeInvalidRequest   = "invalid_request"
+	ErrorCodeLocationNotFound = "location_not_found"
+	ErrorCodeNoMarineData     = "no_marine_data"
+	ErrorCodeNetwork          = "network_error"
+	ErrorCodeOffline          = "offline"
+	ErrorCodeAPI              = "api_error"
+	ErrorCodeRateLimited      = "rate_limited"
+	ErrorCodeBadResponse      = "bad_response"
+	ErrorCodeInternal         = "internal_error"
+)
+
+// ErrorResponse is the JSON form of an error, written by the CLI in JSON
+// mode and by Server.
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failure.
+type ErrorDetail struct {
+	Code      string `json:"code"`      // one of the ErrorCode constants
+	Message   string `json:"message"`   // human-readable; not stable
+	Status    int    `json:"status"`    // HTTP status Server answers with
+	Retryable bool   `json:"retryable"` // whether retrying later may succeed
+}
+
+// NewErrorResponse classifies err.
+func NewErrorResponse(err error) ErrorResponse {
+	d := ErrorDetail{Code: ErrorCodeInternal, Message: err.Error(), Status: http.StatusInternalServerError}
+
+	var usageErr *UsageError
+	var apiErr *APIError
+	var netErr *NetworkError
+	var decodeErr *DecodeError
+	switch {
+	case errors.As(err, &usageErr):
+		d.Code, d.Status = ErrorCodeInvalidRequest, http.StatusBadRequest
+	case errors.Is(err, ErrLocationNotFound):
+		d.Code, d.Status = ErrorCodeLocationNotFound, http.StatusNotFound
+	case errors.Is(err, ErrNoMarineData):
+		d.Code, d.Status = ErrorCodeNoMarineData, http.StatusNotFound
+	case errors.As(err, &apiErr) && apiErr.RateLimited():
+		d.Code, d.Status, d.Retryable = ErrorCodeRateLimited, http.StatusServiceUnavailable, true
+	case errors.As(err, &apiErr):
+		d.Code, d.Status, d.Retryable = ErrorCodeAPI, http.StatusBadGateway, retryableStatus(apiErr.StatusCode)
+	case errors.As(err, &netErr):
+		d.Code, d.Status, d.Retryable = ErrorCodeNetwork, http.StatusGatewayTimeout, true
+	case errors.Is(err, ErrOffline):
+		d.Code, d.Status = ErrorCodeOffline, http.StatusServiceUnavailable
+	case errors.As(err, &decodeErr):
+		d.Code, d.Status = ErrorCodeBadResponse, http.StatusBadGateway
+	}
+	return ErrorResponse{Error: d}
+}
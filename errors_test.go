@@ -0,0 +1,160 @@
+package weathercli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantReason string
+		rateLimit  bool
+	}{
+		{"open-meteo reason", http.StatusBadRequest, `{"error":true,"reason":"Latitude must be in range of -90 to 90°. Given: 91.0."}`, "Latitude must be in range of -90 to 90°. Given: 91.0.", false},
+		{"non-json body", http.StatusBadGateway, `<html>bad gateway</html>`, "Bad Gateway", false},
+		{"rate limited", http.StatusTooManyRequests, `{"error":true,"reason":"Daily API request limit exceeded"}`, "Daily API request limit exceeded", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := NewClient(Options{BaseURL: srv.URL})
+			_, err := client.CurrentByCoords(context.Background(), 91, 0, nil)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Endpoint != "weather" || apiErr.Reason != tt.wantReason {
+				t.Errorf("APIError = %+v, want status %d, endpoint weather, reason %q", apiErr, tt.status, tt.wantReason)
+			}
+			if apiErr.RateLimited() != tt.rateLimit {
+				t.Errorf("RateLimited() = %v, want %v", apiErr.RateLimited(), tt.rateLimit)
+			}
+		})
+	}
+}
+
+func TestLocationNotFoundError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"generationtime_ms":0.5}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{GeoBaseURL: srv.URL})
+	_, err := client.SearchLocation(context.Background(), "Nowhere")
+	if !errors.Is(err, ErrLocationNotFound) {
+		t.Errorf("error = %v, want ErrLocationNotFound", err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"current":`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL})
+	_, err := client.CurrentByCoords(context.Background(), 1, 2, nil)
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Endpoint != "weather" {
+		t.Errorf("error = %v, want weather *DecodeError", err)
+	}
+}
+
+func TestBadTimeIsDecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"timezone":"UTC","current":{"time":"noon","wave_height":1},"hourly":{"time":["noon"]},"daily":{"time":["today"]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL, ArchiveBaseURL: srv.URL, MarineBaseURL: srv.URL, AirQualityBaseURL: srv.URL})
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		endpoint string
+		call     func() error
+	}{
+		{"weather", func() error { _, err := client.CurrentByCoords(ctx, 1, 2, nil); return err }},
+		{"weather", func() error { _, err := client.ForecastByCoords(ctx, 1, 2, 1, true, nil); return err }},
+		{"weather", func() error { _, err := client.ForecastByCoords(ctx, 1, 2, 1, false, nil); return err }},
+		{"archive", func() error { _, err := client.History(ctx, Location{}, day, day); return err }},
+		{"marine", func() error { _, err := client.Marine(ctx, 1, 2, 1, true, nil); return err }},
+		{"air quality", func() error { _, err := client.AirQuality(ctx, 1, 2, 1, nil); return err }},
+	}
+	for _, tt := range tests {
+		err := tt.call()
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Endpoint != tt.endpoint {
+			t.Errorf("error = %v, want %s *DecodeError", err, tt.endpoint)
+		}
+	}
+}
+
+func TestNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL})
+	_, err := client.CurrentByCoords(context.Background(), 1, 2, nil)
+
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) || netErr.Endpoint != "weather" {
+		t.Errorf("error = %v, want weather *NetworkError", err)
+	}
+}
+
+func TestNetworkErrorUnwrapsContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient(Options{BaseURL: srv.URL})
+	_, err := client.CurrentByCoords(ctx, 1, 2, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
+
+func TestNewErrorResponse(t *testing.T) {
+	tests := []struct {
+		err       error
+		code      string
+		status    int
+		retryable bool
+	}{
+		{fmt.Errorf("Atlantis: %w", ErrLocationNotFound), ErrorCodeLocationNotFound, http.StatusNotFound, false},
+		{ErrNoMarineData, ErrorCodeNoMarineData, http.StatusNotFound, false},
+		{&UsageError{Err: errors.New("days must be between 1 and 16")}, ErrorCodeInvalidRequest, http.StatusBadRequest, false},
+		{&APIError{StatusCode: http.StatusTooManyRequests}, ErrorCodeRateLimited, http.StatusServiceUnavailable, true},
+		{&APIError{StatusCode: http.StatusBadRequest}, ErrorCodeAPI, http.StatusBadGateway, false},
+		{&APIError{StatusCode: http.StatusServiceUnavailable}, ErrorCodeAPI, http.StatusBadGateway, true},
+		{&NetworkError{Err: errors.New("connection refused")}, ErrorCodeNetwork, http.StatusGatewayTimeout, true},
+		{fmt.Errorf("weather: %w", ErrOffline), ErrorCodeOffline, http.StatusServiceUnavailable, false},
+		{&DecodeError{Err: errors.New("unexpected EOF")}, ErrorCodeBadResponse, http.StatusBadGateway, false},
+		{errors.New("boom"), ErrorCodeInternal, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		got := NewErrorResponse(tt.err).Error
+		want := ErrorDetail{Code: tt.code, Message: tt.err.Error(), Status: tt.status, Retryable: tt.retryable}
+		if got != want {
+			t.Errorf("NewErrorResponse(%v) = %+v, want %+v", tt.err, got, want)
+		}
+	}
+}
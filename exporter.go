@@ -0,0 +1,207 @@
+package weathercli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExporterOptions configures NewExporter.
+type ExporterOptions struct {
+	// Interval between refreshes. Defaults to 5 minutes.
+	Interval time.Duration
+}
+
+// Exporter periodically fetches current weather for a set of locations and
+// exposes it as Prometheus metrics. Measurements are converted to base
+// units (°C, m/s, mm) whatever the Client's units.
+type Exporter struct {
+	client   *Client
+	interval time.Duration
+
+	mu     sync.Mutex
+	states []exporterState // one per location, in order
+}
+
+type exporterState struct {
+	loc         Location
+	weather     *CurrentWeather // last successful refresh
+	up          bool            // whether the last refresh succeeded
+	latency     time.Duration   // of the last refresh
+	requests    int
+	errors      int
+	lastSuccess time.Time
+}
+
+// NewExporter returns an Exporter for locs. Call Run to start refreshing.
+// Locations without a timezone, such as bare coordinates, take the one the
+// API reports from their first successful refresh; set it beforehand to
+// keep the timezone label stable.
+func NewExporter(c *Client, locs []Location, opts ...ExporterOptions) *Exporter {
+	var opt ExporterOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.Interval == 0 {
+		opt.Interval = 5 * time.Minute
+	}
+	e := &Exporter{client: c, interval: opt.Interval}
+	for _, loc := range locs {
+		e.states = append(e.states, exporterState{loc: loc})
+	}
+	return e
+}
+
+// Run refreshes every location now and then every interval until ctx is
+// done. Refreshes bypass fresh cache entries; failures are counted in the
+// scrape-health metrics.
+func (e *Exporter) Run(ctx context.Context) error {
+	var err error
+	watch(ctx, e.interval, func(ctx context.Context) bool {
+		e.refresh(ctx)
+		return true
+	}, func(fail error) { err = fail })
+	return err
+}
+
+// refresh fetches current weather for each location in turn.
+func (e *Exporter) refresh(ctx context.Context) {
+	for i := range e.states {
+		e.mu.Lock()
+		loc := e.states[i].loc
+		e.mu.Unlock()
+
+		start := time.Now()
+		w, err := e.client.CurrentByCoords(ctx, loc.Latitude, loc.Longitude, &loc)
+		if ctx.Err() != nil {
+			return
+		}
+
+		e.mu.Lock()
+		s := &e.states[i]
+		s.requests++
+		s.latency = time.Since(start)
+		s.up = err == nil
+		if err != nil {
+			s.errors++
+		} else {
+			s.weather = w
+			s.lastSuccess = time.Now()
+			if s.loc.Timezone == "" {
+				s.loc.Timezone = w.Location.Timezone // coordinates carry none
+			}
+		}
+		e.mu.Unlock()
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = e.WriteMetrics(w)
+}
+
+// exporterMetric is one metric family: a value per location, or ok false
+// to leave a location out.
+type exporterMetric struct {
+	name, kind, help string
+	value            func(s exporterState) (v float64, ok bool)
+}
+
+// weatherMetric reads a measurement from the last successful refresh.
+func weatherMetric(name, help string, value func(w *CurrentWeather) float64) exporterMetric {
+	return exporterMetric{name, "gauge", help, func(s exporterState) (float64, bool) {
+		if s.weather == nil {
+			return 0, false
+		}
+		return value(s.weather), true
+	}}
+}
+
+var exporterMetrics = []exporterMetric{
+	weatherMetric("weather_temperature_celsius", "Air temperature at 2 m.", func(w *CurrentWeather) float64 {
+		return w.Units.ToCelsius(w.Temperature)
+	}),
+	weatherMetric("weather_apparent_temperature_celsius", "Feels-like temperature.", func(w *CurrentWeather) float64 {
+		return w.Units.ToCelsius(w.Apparent)
+	}),
+	weatherMetric("weather_humidity_percent", "Relative humidity at 2 m.", func(w *CurrentWeather) float64 {
+		return float64(w.Humidity)
+	}),
+	weatherMetric("weather_wind_speed_meters_per_second", "Wind speed at 10 m.", func(w *CurrentWeather) float64 {
+		return w.Units.ToKmh(w.WindSpeed) / 3.6
+	}),
+	weatherMetric("weather_wind_direction_degrees", "Wind direction at 10 m.", func(w *CurrentWeather) float64 {
+		return float64(w.WindDirection)
+	}),
+	weatherMetric("weather_pressure_hpa", "Sea-level air pressure.", func(w *CurrentWeather) float64 {
+		return w.Pressure
+	}),
+	weatherMetric("weather_cloud_cover_percent", "Total cloud cover.", func(w *CurrentWeather) float64 {
+		return float64(w.CloudCover)
+	}),
+	weatherMetric("weather_uv_index", "UV index.", func(w *CurrentWeather) float64 {
+		return w.UVIndex
+	}),
+	weatherMetric("weather_precipitation_millimeters", "Precipitation in the preceding hour.", func(w *CurrentWeather) float64 {
+		if w.Units.Precipitation == Inches {
+			return w.Precipitation * 25.4
+		}
+		return w.Precipitation
+	}),
+	{"weather_up", "gauge", "Whether the last refresh of the location succeeded.", func(s exporterState) (float64, bool) {
+		return boolFloat(s.up), s.requests > 0
+	}},
+	{"weather_api_latency_seconds", "gauge", "Duration of the last API request for the location.", func(s exporterState) (float64, bool) {
+		return s.latency.Seconds(), s.requests > 0
+	}},
+	{"weather_api_requests_total", "counter", "API requests made for the location.", func(s exporterState) (float64, bool) {
+		return float64(s.requests), true
+	}},
+	{"weather_api_errors_total", "counter", "Failed API requests for the location.", func(s exporterState) (float64, bool) {
+		return float64(s.errors), true
+	}},
+	{"weather_last_success_timestamp_seconds", "gauge", "Unix time of the last successful refresh.", func(s exporterState) (float64, bool) {
+		return float64(s.lastSuccess.UnixMilli()) / 1000, !s.lastSuccess.IsZero()
+	}},
+}
+
+// WriteMetrics writes the current metrics in the Prometheus text format.
+func (e *Exporter) WriteMetrics(w io.Writer) error {
+	e.mu.Lock()
+	states := append([]exporterState(nil), e.states...)
+	e.mu.Unlock()
+
+	b := bufio.NewWriter(w)
+	for _, m := range exporterMetrics {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, s := range states {
+			if v, ok := m.value(s); ok {
+				fmt.Fprintf(b, "%s{%s} %s\n", m.name, metricLabels(s.loc), strconv.FormatFloat(v, 'f', -1, 64))
+			}
+		}
+	}
+	return b.Flush()
+}
+
+func metricLabels(loc Location) string {
+	return fmt.Sprintf(`location="%s",country="%s",timezone="%s"`,
+		escapeLabel(loc.Name), escapeLabel(loc.Country), escapeLabel(loc.Timezone))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
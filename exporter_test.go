@@ -0,0 +1,79 @@
+package weathercli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExporterMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Query().Get("latitude"), "40.71") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":true,"reason":"Invalid latitude"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"latitude":40.71,"longitude":-74.01,"timezone":"America/New_York","current":{"time":"2024-01-12T09:00","temperature_2m":41,"wind_speed_10m":9,"relative_humidity_2m":80,"precipitation":0.1}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL, Units: ImperialUnits()})
+	e := NewExporter(client, []Location{
+		{Name: "New York", Country: "United States", Timezone: "America/New_York", Latitude: 40.71, Longitude: -74.01},
+		{Name: `Bad "place"`, Latitude: 1, Longitude: 2},
+	})
+	e.refresh(context.Background())
+
+	var b strings.Builder
+	if err := e.WriteMetrics(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	ny := `{location="New York",country="United States",timezone="America/New_York"}`
+	bad := `{location="Bad \"place\"",country="",timezone=""}`
+	for _, want := range []string{
+		"# TYPE weather_temperature_celsius gauge",
+		"weather_temperature_celsius" + ny + " 5\n",
+		"weather_humidity_percent" + ny + " 80\n",
+		"weather_wind_speed_meters_per_second" + ny + " 4.02336\n",
+		"weather_precipitation_millimeters" + ny + " 2.54\n",
+		"weather_up" + ny + " 1\n",
+		"weather_up" + bad + " 0\n",
+		"# TYPE weather_api_errors_total counter",
+		"weather_api_errors_total" + bad + " 1\n",
+		"weather_api_requests_total" + ny + " 1\n",
+		"weather_last_success_timestamp_seconds" + ny,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"weather_temperature_celsius" + bad, "weather_last_success_timestamp_seconds" + bad} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("metrics contain %q for a location that never succeeded", unwanted)
+		}
+	}
+}
+
+func TestExporterCoordinateLabels(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"latitude":52.52,"longitude":13.41,"timezone":"Europe/Berlin","current":{"time":"2024-01-12T09:00","temperature_2m":3}}`))
+	}))
+	defer srv.Close()
+
+	// Bare coordinates resolve to a Location with no timezone or country.
+	e := NewExporter(NewClient(Options{BaseURL: srv.URL}), []Location{{Name: "52.5200, 13.4100", Latitude: 52.52, Longitude: 13.41}})
+	e.refresh(context.Background())
+
+	var b strings.Builder
+	if err := e.WriteMetrics(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `weather_temperature_celsius{location="52.5200, 13.4100",country="",timezone="Europe/Berlin"} 3` + "\n"
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("metrics missing %q:\n%s", want, b.String())
+	}
+}
@@ -5,10 +5,8 @@ go 1.23
 require (
 	github.com/alecthomas/kong v1.6.1
 	github.com/fatih/color v1.18.0
+	github.com/mattn/go-isatty v0.0.20
+	golang.org/x/sys v0.29.0
 )
 
-require (
-	github.com/mattn/go-colorable v0.1.14 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
-	golang.org/x/sys v0.29.0 // indirect
-)
+require github.com/mattn/go-colorable v0.1.14 // indirect
@@ -0,0 +1,71 @@
+package weathercli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Variables requested from the archive API. The archive has no
+// precipitation probability or UV index.
+const (
+	archiveHourlyFields = "temperature_2m,relative_humidity_2m,apparent_temperature,precipitation,rain,snowfall,weather_code,pressure_msl,cloud_cover,wind_speed_10m,wind_direction_10m"
+	archiveDailyFields  = "temperature_2m_max,temperature_2m_min,apparent_temperature_max,apparent_temperature_min,sunrise,sunset,precipitation_sum,rain_sum,snowfall_sum,weather_code,wind_speed_10m_max,wind_direction_10m_dominant"
+)
+
+// HistoryOptions configures a historical weather lookup.
+type HistoryOptions struct {
+	Hourly bool // Return hourly data instead of daily
+}
+
+// History fetches observed weather for loc between start and end
+// (inclusive, by calendar date). The result uses the same shapes as
+// ForecastByCoords: Daily by default, Hourly when opts.Hourly is set.
+func (c *Client) History(ctx context.Context, loc Location, start, end time.Time, opts ...HistoryOptions) (*Forecast, error) {
+	var opt HistoryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	startDate := start.Format(dateLayout)
+	endDate := end.Format(dateLayout)
+	if endDate < startDate {
+		return nil, fmt.Errorf("history end date %s is before start date %s", endDate, startDate)
+	}
+
+	u, err := url.Parse(c.archiveBaseURL + "/archive")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("latitude", fmt.Sprintf("%.4f", loc.Latitude))
+	q.Set("longitude", fmt.Sprintf("%.4f", loc.Longitude))
+	q.Set("start_date", startDate)
+	q.Set("end_date", endDate)
+	q.Set("timezone", "auto")
+	c.units.setQuery(q)
+
+	if opt.Hourly {
+		q.Set("hourly", archiveHourlyFields)
+	} else {
+		q.Set("daily", archiveDailyFields)
+	}
+
+	u.RawQuery = q.Encode()
+
+	var result seriesResponse
+	meta, err := c.getJSON(ctx, u, "archive", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := result.forecast("archive", loc.Latitude, loc.Longitude, &loc, c.units)
+	if err != nil {
+		return nil, err
+	}
+	history.Stale, history.CachedAt = meta.staleSince()
+
+	return history, nil
+}
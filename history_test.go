@@ -0,0 +1,76 @@
+package weathercli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistoryDaily(t *testing.T) {
+	var gotPath, gotStart, gotEnd, gotDaily string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotStart = r.URL.Query().Get("start_date")
+		gotEnd = r.URL.Query().Get("end_date")
+		gotDaily = r.URL.Query().Get("daily")
+		_, _ = w.Write([]byte(`{"timezone":"Europe/Lisbon","daily":{
+			"time":["2024-01-01","2024-01-02"],
+			"temperature_2m_max":[15.1,14.0],
+			"temperature_2m_min":[9.2,8.7],
+			"sunrise":["2024-01-01T07:55","2024-01-02T07:55"],
+			"sunset":["2024-01-01T17:25","2024-01-02T17:26"],
+			"precipitation_sum":[0.0,3.4],
+			"weather_code":[1,61]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{ArchiveBaseURL: srv.URL})
+	loc := Location{Name: "Lisbon", Latitude: 38.7167, Longitude: -9.1333}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	history, err := client.History(context.Background(), loc, start, end)
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+
+	if gotPath != "/archive" {
+		t.Errorf("path = %q, want /archive", gotPath)
+	}
+	if gotStart != "2024-01-01" || gotEnd != "2024-01-02" {
+		t.Errorf("dates = %s..%s, want 2024-01-01..2024-01-02", gotStart, gotEnd)
+	}
+	if gotDaily != archiveDailyFields {
+		t.Errorf("daily = %q, want archive fields", gotDaily)
+	}
+	if history.Location.Name != "Lisbon" {
+		t.Errorf("Location.Name = %q, want Lisbon", history.Location.Name)
+	}
+	if len(history.Daily) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(history.Daily))
+	}
+
+	day := history.Daily[1]
+	if day.Precipitation != 3.4 || day.Condition != "Slight rain" {
+		t.Errorf("Day 1 = %+v, want 3.4 mm slight rain", day)
+	}
+	// Variables the archive does not provide decode as zero.
+	if day.PrecipProb != 0 || day.UVIndexMax != 0 {
+		t.Errorf("Day 1 missing variables = %d%% / UV %.1f, want zero", day.PrecipProb, day.UVIndexMax)
+	}
+	if day.Sunset.Format("15:04") != "17:26" {
+		t.Errorf("Day 1 sunset = %s, want 17:26", day.Sunset.Format("15:04"))
+	}
+}
+
+func TestHistoryRejectsReversedRange(t *testing.T) {
+	client := NewClient(Options{ArchiveBaseURL: "http://127.0.0.1:0"})
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := client.History(context.Background(), Location{}, start, end); err == nil {
+		t.Error("Expected error for end before start")
+	}
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// exitCodeAlertsFired is returned by the alert command when a rule fires.
+const exitCodeAlertsFired = 3
+
+// alertReport is the JSON output of the alert command.
+type alertReport struct {
+	Location weathercli.Location     `json:"location"`
+	Units    weathercli.Units        `json:"units"`
+	Fired    bool                    `json:"fired"`
+	Results  []weathercli.RuleResult `json:"results"`
+}
+
+// Run for AlertCmd.
+func (c *AlertCmd) Run(app *App, ctx context.Context) error {
+	rules, err := c.rules()
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return usagef("no rules given (use --rule or --rules-file)")
+	}
+	if c.Days < 1 || c.Days > 16 {
+		return usagef("days must be between 1 and 16")
+	}
+
+	// Fetch only the series the rules need, covering the longest window.
+	var needDaily, needHourly bool
+	days := c.Days
+	for _, r := range rules {
+		if r.UsesHourly() {
+			needHourly = true
+		} else {
+			needDaily = true
+		}
+		if r.Within > 0 {
+			if d := int(r.Within.Hours()+23)/24 + 1; d > days {
+				days = min(d, 16)
+			}
+		}
+	}
+
+	if app.verbose {
+		app.renderVerbose("Checking %d rule(s) against %d-day forecast for: %s", len(rules), days, c.Location)
+	}
+
+	loc, err := app.resolveLocation(ctx, c.Location, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+
+	forecast := &weathercli.Forecast{Location: loc}
+	if needDaily {
+		daily, err := app.client.ForecastByCoords(ctx, loc.Latitude, loc.Longitude, days, false, &loc)
+		if err != nil {
+			return err
+		}
+		forecast.Location = daily.Location
+		forecast.Units = daily.Units
+		forecast.Daily = daily.Daily
+	}
+	if needHourly {
+		hourly, err := app.client.ForecastByCoords(ctx, loc.Latitude, loc.Longitude, days, true, &loc)
+		if err != nil {
+			return err
+		}
+		forecast.Location = hourly.Location
+		forecast.Units = hourly.Units
+		forecast.Hourly = hourly.Hourly
+	}
+
+	// Evaluate in the forecast's timezone, which the API resolves even for
+	// coordinates, so daily rules start from the location's today.
+	now := time.Now()
+	if tz, err := time.LoadLocation(forecast.Location.Timezone); err == nil {
+		now = now.In(tz)
+	}
+
+	report := alertReport{
+		Location: forecast.Location,
+		Units:    forecast.Units,
+		Results:  weathercli.EvaluateRules(forecast, rules, now),
+	}
+	for _, r := range report.Results {
+		report.Fired = report.Fired || r.Fired
+	}
+
+	if err := app.RenderAlerts(report); err != nil {
+		return err
+	}
+	if report.Fired {
+		return exitError{code: exitCodeAlertsFired}
+	}
+	return nil
+}
+
+// rules collects rules from --rule flags and --rules-file.
+func (c *AlertCmd) rules() ([]weathercli.Rule, error) {
+	var rules []weathercli.Rule
+	for _, expr := range c.Rules {
+		rule, err := weathercli.ParseRule(expr)
+		if err != nil {
+			return nil, &weathercli.UsageError{Err: err}
+		}
+		rules = append(rules, rule)
+	}
+
+	if c.RulesFile != "" {
+		f, err := os.Open(c.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		fileRules, err := weathercli.ParseRules(f)
+		if err != nil {
+			return nil, &weathercli.UsageError{Err: fmt.Errorf("%s: %w", c.RulesFile, err)}
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	return rules, nil
+}
+
+// RenderAlerts outputs rule results in human or JSON format.
+func (a *App) RenderAlerts(report alertReport) error {
+	if a.structured() {
+		return a.renderData(report)
+	}
+
+	fmt.Fprintf(a.out, "%s\n\n", a.color.Bold(locationLabel(report.Location)))
+
+	for _, r := range report.Results {
+		if !r.Fired {
+			fmt.Fprintf(a.out, "%s  %s\n", a.color.Green("ok   "), r.Rule.Expr)
+			continue
+		}
+
+		fmt.Fprintf(a.out, "%s  %s\n", a.color.Red("FIRED"), a.color.Bold(r.Rule.Expr))
+		for _, m := range r.Matches {
+			when := m.Time.Format("Mon Jan 2 15:04")
+			if m.Series == "daily" {
+				when = m.Time.Format("Mon Jan 2")
+			}
+			fmt.Fprintf(a.out, "       %s %s\n", a.color.Cyan(fmt.Sprintf("%-16s", when)), formatRuleValue(r.Rule.Metric, m.Value, report.Units))
+		}
+	}
+
+	return nil
+}
+
+// formatRuleValue formats a matched value with the unit of its metric.
+func formatRuleValue(metric string, v float64, units weathercli.Units) string {
+	switch metric {
+	case "temp", "temp_max", "temp_min", "apparent", "apparent_max", "apparent_min":
+		return fmt.Sprintf("%.1f%s", v, units.TemperatureSymbol())
+	case "precip", "rain":
+		return formatPrecip(v, units)
+	case "snowfall":
+		return fmt.Sprintf("%.1f %s", v, units.SnowfallSymbol())
+	case "wind_speed", "wind_speed_max":
+		return fmt.Sprintf("%.1f %s", v, units.WindSpeedSymbol())
+	case "pressure":
+		return fmt.Sprintf("%.0f hPa", v)
+	case "humidity", "precip_prob", "cloud_cover":
+		return fmt.Sprintf("%.0f%%", v)
+	default:
+		return fmt.Sprintf("%.1f", v)
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+func TestAlertUsesForecastTimezone(t *testing.T) {
+	// Pick a zone whose today differs from UTC's right now: Pago Pago
+	// (UTC-11) before 11:00 UTC, Kiritimati (UTC+14) after. Only the
+	// location's today is below freezing.
+	zone := "Pacific/Kiritimati"
+	if time.Now().UTC().Hour() < 11 {
+		zone = "Pacific/Pago_Pago"
+	}
+	tz, err := time.LoadLocation(zone)
+	if err != nil {
+		t.Skip(err)
+	}
+	today := time.Now().In(tz)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"latitude":1.87,"longitude":-157.4,"timezone":%q,
+			"daily":{"time":[%q,%q,%q],"temperature_2m_min":[3,-5,3]}}`,
+			zone, today.AddDate(0, 0, -1).Format("2006-01-02"), today.Format("2006-01-02"), today.AddDate(0, 0, 1).Format("2006-01-02"))
+	}))
+	defer srv.Close()
+
+	var out strings.Builder
+	app := &App{
+		client: weathercli.NewClient(weathercli.Options{BaseURL: srv.URL}),
+		out:    &out,
+		err:    io.Discard,
+		format: formatJSON,
+		color:  NewColor(false),
+	}
+	cmd := &AlertCmd{Location: "1.87,-157.4", Rules: []string{"temp_min < 0 within 1d"}, Days: 3}
+	err = cmd.Run(app, context.Background())
+	var exit exitError
+	if !errors.As(err, &exit) || exit.code != exitCodeAlertsFired {
+		t.Fatalf("Run error = %v, want exit %d\n%s", err, exitCodeAlertsFired, out.String())
+	}
+
+	var report struct {
+		Location weathercli.Location `json:"location"`
+		Results  []struct {
+			Rule map[string]any `json:"rule"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Location.Timezone != zone {
+		t.Errorf("location timezone = %q, want %s", report.Location.Timezone, zone)
+	}
+	if len(report.Results) != 1 || report.Results[0].Rule["expr"] != "temp_min < 0 within 1d" || report.Results[0].Rule["within"] != "1d" {
+		t.Errorf("results = %+v, want the rule's expr and window", report.Results)
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// batchResult is one location of a multi-location query in JSON output.
+type batchResult struct {
+	Query  string                  `json:"query"`
+	Result any                     `json:"result,omitempty"`
+	Error  *weathercli.ErrorDetail `json:"error,omitempty"`
+}
+
+// locationQueries collects the location arguments and the lines of the
+// --from-file file ('-' is stdin), skipping blank lines and '#' comments.
+func (a *App) locationQueries(args []string, fromFile string) ([]string, error) {
+	var queries []string
+	for _, arg := range args {
+		if strings.TrimSpace(arg) != "" {
+			queries = append(queries, arg)
+		}
+	}
+	if fromFile == "" {
+		return queries, nil
+	}
+
+	var r io.Reader = a.in
+	if fromFile != "-" {
+		f, err := os.Open(fromFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		queries = append(queries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", fromFile, err)
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no locations in %s", fromFile)
+	}
+	return queries, nil
+}
+
+// batchOptions resolves batch queries like single ones, without prompting:
+// several lookups run at once, so ambiguous names use the best match.
+func (a *App) batchOptions(flags LocationFlags) weathercli.BatchOptions {
+	quiet := *a
+	quiet.interactive = false
+	return weathercli.BatchOptions{
+		Resolve: func(ctx context.Context, query string) (weathercli.Location, error) {
+			return quiet.resolveLocation(ctx, query, flags)
+		},
+	}
+}
+
+// renderBatch outputs multi-location results in input order: a list of
+// {query, result|error} records in structured formats, or each result
+// rendered in turn (or through --template) with failures reported on
+// stderr. If any location failed, it returns an exitError with the code
+// of the first failure.
+func (a *App) renderBatch(queries []string, results []any, errs []error, render func(i int) error) error {
+	var firstErr error
+	for _, err := range errs {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	switch {
+	case a.structured() && a.format != formatTemplate:
+		out := make([]batchResult, len(queries))
+		for i, query := range queries {
+			out[i] = batchResult{Query: query, Result: results[i]}
+			if errs[i] != nil {
+				detail := weathercli.NewErrorResponse(errs[i]).Error
+				out[i] = batchResult{Query: query, Error: &detail}
+			}
+		}
+		if err := a.renderData(out); err != nil {
+			return err
+		}
+	default:
+		rendered := false
+		for i, query := range queries {
+			if errs[i] != nil {
+				fmt.Fprintf(a.err, "%s %s: %v\n", a.color.Red("Error:"), query, errs[i])
+				continue
+			}
+			if rendered && !a.structured() {
+				fmt.Fprintln(a.out)
+			}
+			if err := render(i); err != nil {
+				return err
+			}
+			rendered = true
+		}
+	}
+
+	if firstErr != nil {
+		return exitError{code: exitCode(firstErr)}
+	}
+	return nil
+}
+
+// runMany runs CurrentCmd for several locations.
+func (c *CurrentCmd) runMany(ctx context.Context, app *App, queries []string) error {
+	if app.verbose {
+		app.renderVerbose("Fetching current weather for %d locations: %s", len(queries), strings.Join(queries, "; "))
+	}
+
+	results := app.client.CurrentMany(ctx, queries, app.batchOptions(c.LocationFlags))
+	values := make([]any, len(results))
+	errs := make([]error, len(results))
+	for i, r := range results {
+		values[i], errs[i] = r.Weather, r.Err
+	}
+	return app.renderBatch(queries, values, errs, func(i int) error {
+		return app.RenderCurrentWeather(results[i].Weather)
+	})
+}
+
+// runMany runs ForecastCmd for several locations.
+func (c *ForecastCmd) runMany(ctx context.Context, app *App, queries []string, days int) error {
+	if app.verbose {
+		app.renderVerbose("Fetching %d-day forecast for %d locations: %s", days, len(queries), strings.Join(queries, "; "))
+	}
+
+	results := app.client.ForecastMany(ctx, queries, days, c.Hourly, app.batchOptions(c.LocationFlags))
+	values := make([]any, len(results))
+	errs := make([]error, len(results))
+	for i, r := range results {
+		if r.Forecast != nil && c.Hourly && len(r.Forecast.Hourly) > c.Hours {
+			r.Forecast.Hourly = r.Forecast.Hourly[:c.Hours]
+		}
+		values[i], errs[i] = r.Forecast, r.Err
+	}
+	return app.renderBatch(queries, values, errs, func(i int) error {
+		return app.RenderForecast(results[i].Forecast)
+	})
+}
@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// ChartCmd draws an hourly series in the terminal.
+type ChartCmd struct {
+	Location      string `arg:"" optional:"" name:"location" env:"WEATHER_LOCATION" default:"${default_location}" help:"Location name, coordinates or id:N (e.g. 'Berlin', 'Denver, Colorado')."`
+	LocationFlags `embed:""`
+	Metric        string `help:"Series to chart (temperature, precip, wind, pressure)." enum:"temperature,precip,wind,pressure" default:"temperature"`
+	Hours         int    `help:"Number of hours to chart (1-384)." default:"48"`
+	Height        int    `help:"Chart height in rows (3-40)." default:"10"`
+}
+
+// chartSeries is an hourly series of one metric.
+type chartSeries struct {
+	Location weathercli.Location `json:"location"`
+	Metric   string              `json:"metric"`
+	Unit     string              `json:"unit"`
+	Points   []chartPoint        `json:"points"`
+}
+
+type chartPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// maxSparklineWidth caps sparklines when the output width is unknown.
+const maxSparklineWidth = 72
+
+// sparkBlocks are the eight block heights used by sparklines and charts.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Run for ChartCmd.
+func (c *ChartCmd) Run(app *App, ctx context.Context) error {
+	if c.Hours < 1 || c.Hours > 384 {
+		return usagef("hours must be between 1 and 384")
+	}
+	if c.Height < 3 || c.Height > 40 {
+		return usagef("height must be between 3 and 40")
+	}
+	if app.verbose {
+		app.renderVerbose("Fetching %d-hour %s series for: %s", c.Hours, c.Metric, c.Location)
+	}
+
+	loc, err := app.resolveLocation(ctx, c.Location, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+	forecast, err := app.client.ForecastByCoords(ctx, loc.Latitude, loc.Longitude, min(16, (c.Hours+23)/24), true, &loc)
+	if err != nil {
+		return err
+	}
+
+	hours := forecast.Hourly[:min(c.Hours, len(forecast.Hourly))]
+	series := chartSeries{Location: forecast.Location, Metric: c.Metric, Unit: metricUnit(c.Metric, forecast.Units)}
+	for _, h := range hours {
+		series.Points = append(series.Points, chartPoint{Time: h.Time, Value: metricValue(c.Metric, h)})
+	}
+	return app.RenderChart(series, c.Height)
+}
+
+func metricValue(metric string, h weathercli.HourlyForecast) float64 {
+	switch metric {
+	case "precip":
+		return h.Precipitation
+	case "wind":
+		return h.WindSpeed
+	case "pressure":
+		return h.Pressure
+	}
+	return h.Temperature
+}
+
+func metricUnit(metric string, units weathercli.Units) string {
+	switch metric {
+	case "precip":
+		return units.PrecipitationSymbol()
+	case "wind":
+		return units.WindSpeedSymbol()
+	case "pressure":
+		return "hPa"
+	}
+	return units.TemperatureSymbol()
+}
+
+// RenderChart outputs a block chart of the series with a value axis, a
+// time axis in the location's timezone and min/max markers, or the series
+// as data.
+func (a *App) RenderChart(s chartSeries, height int) error {
+	if a.structured() {
+		return a.renderData(s)
+	}
+	if len(s.Points) == 0 {
+		return fmt.Errorf("no hourly data to chart")
+	}
+
+	fmt.Fprintf(a.out, "%s\n", a.color.Bold(locationLabel(s.Location)))
+	fmt.Fprintf(a.out, "%s\n\n", a.color.Cyan(fmt.Sprintf("%s (%s), %d hours", s.Metric, s.Unit, len(s.Points))))
+
+	values := make([]float64, len(s.Points))
+	for i, p := range s.Points {
+		values[i] = p.Value
+	}
+	lo, hi := minMax(values)
+	labelWidth := max(len(formatChartValue(lo)), len(formatChartValue(hi)))
+
+	// Precipitation keeps its peaks when several hours share a column.
+	width := len(values)
+	if a.width > 0 {
+		width = min(width, a.width-labelWidth-2)
+	}
+	columns, starts := downsample(values, width, s.Metric == "precip")
+	loCol, hiCol := argMinMax(columns)
+
+	span := hi - lo
+	levels := make([]int, len(columns)) // eighths of a row, 1..height*8
+	for i, v := range columns {
+		levels[i] = height * 8
+		if span > 0 {
+			levels[i] = 1 + int(math.Round((v-lo)/span*float64(height*8-1)))
+		}
+	}
+
+	for row := height - 1; row >= 0; row-- {
+		label := ""
+		switch row {
+		case height - 1:
+			label = formatChartValue(hi)
+		case 0:
+			label = formatChartValue(lo)
+		}
+		var line strings.Builder
+		for i, level := range levels {
+			cell := " "
+			switch fill := level - row*8; {
+			case fill >= 8:
+				cell = string(sparkBlocks[7])
+			case fill > 0:
+				cell = string(sparkBlocks[fill-1])
+			}
+			switch i {
+			case hiCol:
+				cell = a.color.Red(cell)
+			case loCol:
+				cell = a.color.Blue(cell)
+			}
+			line.WriteString(cell)
+		}
+		fmt.Fprintf(a.out, "%*s │%s\n", labelWidth, label, strings.TrimRight(line.String(), " "))
+	}
+
+	fmt.Fprintf(a.out, "%*s └%s\n", labelWidth, "", strings.Repeat("─", len(columns)))
+	fmt.Fprintln(a.out, strings.TrimRight(fmt.Sprintf("%*s  %s", labelWidth, "", timeAxis(s.Points, starts)), " "))
+
+	markers := []rune(strings.Repeat(" ", len(columns)))
+	markers[loCol], markers[hiCol] = '▼', '▲'
+	fmt.Fprintf(a.out, "%*s  %s\n\n", labelWidth, "", strings.TrimRight(string(markers), " "))
+
+	peak, low := s.Points[argMax(values)], s.Points[argMin(values)]
+	fmt.Fprintf(a.out, "%s %s %s at %s\n", a.color.Red("▲"), a.color.Bold("Max:"), formatChartValue(peak.Value)+" "+s.Unit, peak.Time.Format("Mon 15:04 MST"))
+	fmt.Fprintf(a.out, "%s %s %s at %s\n", a.color.Blue("▼"), a.color.Bold("Min:"), formatChartValue(low.Value)+" "+s.Unit, low.Time.Format("Mon 15:04 MST"))
+	return nil
+}
+
+// timeAxis labels chart columns: the weekday at midnight and the hour
+// every few hours, spaced so labels do not overlap.
+func timeAxis(points []chartPoint, starts []int) string {
+	hoursPerColumn := 1
+	if len(starts) > 1 {
+		hoursPerColumn = starts[1] - starts[0]
+	}
+	step := 24
+	for _, s := range []int{3, 6, 12} {
+		if s/hoursPerColumn >= 4 {
+			step = s
+			break
+		}
+	}
+
+	axis := []rune(strings.Repeat(" ", len(starts)))
+	next := 0
+	for col, start := range starts {
+		end := len(points)
+		if col+1 < len(starts) {
+			end = starts[col+1]
+		}
+		for _, p := range points[start:end] {
+			if p.Time.Hour()%step != 0 || col < next {
+				continue
+			}
+			label := p.Time.Format("15")
+			if p.Time.Hour() == 0 {
+				label = p.Time.Format("Mon")
+			}
+			if col+utf8.RuneCountInString(label) > len(axis) {
+				break
+			}
+			copy(axis[col:], []rune(label))
+			next = col + utf8.RuneCountInString(label) + 1
+			break
+		}
+	}
+	return strings.TrimRight(string(axis), " ")
+}
+
+// downsample reduces values to at most width columns by averaging (or
+// taking the maximum of) consecutive values. It returns the columns and
+// the index of the first value in each.
+func downsample(values []float64, width int, peak bool) ([]float64, []int) {
+	per := 1
+	if width > 0 && len(values) > width {
+		per = (len(values) + width - 1) / width
+	}
+	var columns []float64
+	var starts []int
+	for start := 0; start < len(values); start += per {
+		bucket := values[start:min(start+per, len(values))]
+		v := bucket[0]
+		if peak {
+			_, v = minMax(bucket)
+		} else {
+			sum := 0.0
+			for _, b := range bucket {
+				sum += b
+			}
+			v = sum / float64(len(bucket))
+		}
+		columns = append(columns, v)
+		starts = append(starts, start)
+	}
+	return columns, starts
+}
+
+// sparkline draws values as a row of block characters, at most width wide
+// (0 for no limit).
+func sparkline(values []float64, width int) string {
+	columns, _ := downsample(values, width, false)
+	lo, hi := minMax(columns)
+	var b strings.Builder
+	for _, v := range columns {
+		i := 0
+		if hi > lo {
+			i = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// renderSparkline prints a temperature trend line for an hourly series.
+func (a *App) renderSparkline(hours []weathercli.HourlyForecast, units weathercli.Units) {
+	if len(hours) < 2 {
+		return
+	}
+	temps := make([]float64, len(hours))
+	for i, h := range hours {
+		temps[i] = h.Temperature
+	}
+	lo, hi := minMax(temps)
+	width := maxSparklineWidth
+	if a.width > 0 {
+		// Leave room for the label and the range.
+		width = max(8, min(width, a.width-30))
+	}
+	fmt.Fprintf(a.out, "%s %s  %s – %s\n\n", a.color.Cyan("Trend:"), sparkline(temps, width),
+		formatTemp(lo, units, a.color), formatTemp(hi, units, a.color))
+}
+
+func formatChartValue(v float64) string {
+	return fmt.Sprintf("%.1f", v)
+}
+
+func minMax(values []float64) (float64, float64) {
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return lo, hi
+}
+
+func argMinMax(values []float64) (int, int) {
+	return argMin(values), argMax(values)
+}
+
+func argMin(values []float64) int {
+	i := 0
+	for j, v := range values {
+		if v < values[i] {
+			i = j
+		}
+	}
+	return i
+}
+
+func argMax(values []float64) int {
+	i := 0
+	for j, v := range values {
+		if v > values[i] {
+			i = j
+		}
+	}
+	return i
+}
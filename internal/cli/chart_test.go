@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+func testChartSeries(metric string, values ...float64) chartSeries {
+	tz := time.FixedZone("CET", 3600)
+	s := chartSeries{Location: weathercli.Location{Name: "Berlin", Country: "Germany"}, Metric: metric, Unit: "°C"}
+	for i, v := range values {
+		s.Points = append(s.Points, chartPoint{Time: time.Date(2024, 1, 12, 21, 0, 0, 0, tz).Add(time.Duration(i) * time.Hour), Value: v})
+	}
+	return s
+}
+
+func TestRenderChart(t *testing.T) {
+	temps := []float64{2, 1, 0, -1, -1.5, -1, 0, 1.5, 3, 4, 4.5, 4}
+	tests := []struct {
+		name   string
+		series chartSeries
+		width  int
+		height int
+		want   string
+	}{
+		{
+			name:   "one column per hour",
+			series: testChartSeries("temperature", temps...),
+			height: 4,
+			want: `Berlin, Germany
+temperature (°C), 12 hours
+
+ 4.5 │         ▅█▅
+     │▃      ▁████
+     │█▆▁   ▁█████
+-1.5 │███▄▁▄██████
+     └────────────
+         Sat   06
+          ▼     ▲
+
+▲ Max: 4.5 °C at Sat 07:00 CET
+▼ Min: -1.5 °C at Sat 01:00 CET
+`,
+		},
+		{
+			// 10 columns leave 4 for the plot: three hours per column,
+			// averaged, on the scale of the hourly extremes.
+			name:   "downsampled",
+			series: testChartSeries("temperature", temps...),
+			width:  10,
+			height: 4,
+			want: `Berlin, Germany
+temperature (°C), 12 hours
+
+ 4.5 │   ▆
+     │  ▁█
+     │▆ ██
+-1.5 │█▃██
+     └────
+       Sat
+       ▼ ▲
+
+▲ Max: 4.5 °C at Sat 07:00 CET
+▼ Min: -1.5 °C at Sat 01:00 CET
+`,
+		},
+		{
+			// Precipitation columns keep the peak of their hours; there
+			// is no room for the midnight label.
+			name:   "precipitation peaks",
+			series: testChartSeries("precip", 0, 0, 2, 0, 0, 0, 0, 1, 0),
+			width:  9,
+			height: 3,
+			want: `Berlin, Germany
+precip (°C), 9 hours
+
+2.0 │█
+    │█ ▅
+0.0 │█▁█
+    └───
+
+     ▲▼
+
+▲ Max: 2.0 °C at Fri 23:00 CET
+▼ Min: 0.0 °C at Fri 21:00 CET
+`,
+		},
+		{
+			name:   "flat",
+			series: testChartSeries("temperature", 3, 3, 3),
+			height: 3,
+			want: `Berlin, Germany
+temperature (°C), 3 hours
+
+3.0 │███
+    │███
+3.0 │███
+    └───
+
+     ▲
+
+▲ Max: 3.0 °C at Fri 21:00 CET
+▼ Min: 3.0 °C at Fri 21:00 CET
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		var out strings.Builder
+		app := &App{out: &out, format: formatHuman, color: NewColor(false), width: tt.width}
+		if err := app.RenderChart(tt.series, tt.height); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+
+	app := &App{out: &strings.Builder{}, format: formatHuman, color: NewColor(false)}
+	if err := app.RenderChart(testChartSeries("temperature"), 10); err == nil {
+		t.Error("RenderChart with no points succeeded, want an error")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, 0, "▁▂▃▄▅▆▇█"},
+		{[]float64{-10, 10}, 0, "▁█"},
+		{[]float64{5, 5, 5}, 0, "▁▁▁"},
+		// Pairs are averaged to fit four columns: 0.5, 2.5, 4.5, 6.5.
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, 4, "▁▃▆█"},
+		{[]float64{0, 1, 2, 3, 4}, 2, "▁█"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values, tt.width); got != tt.want {
+			t.Errorf("sparkline(%v, %d) = %s, want %s", tt.values, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	tests := []struct {
+		values     []float64
+		width      int
+		peak       bool
+		wantValues []float64
+		wantStarts []int
+	}{
+		{[]float64{1, 2, 3}, 0, false, []float64{1, 2, 3}, []int{0, 1, 2}},
+		{[]float64{1, 2, 3}, 5, false, []float64{1, 2, 3}, []int{0, 1, 2}},
+		{[]float64{1, 3, 5, 7, 9}, 2, false, []float64{3, 8}, []int{0, 3}},
+		{[]float64{0, 4, 1, 0, 2}, 3, false, []float64{2, 0.5, 2}, []int{0, 2, 4}},
+		{[]float64{0, 4, 1, 0, 2}, 3, true, []float64{4, 1, 2}, []int{0, 2, 4}},
+	}
+	for _, tt := range tests {
+		values, starts := downsample(tt.values, tt.width, tt.peak)
+		if fmt.Sprint(values) != fmt.Sprint(tt.wantValues) || fmt.Sprint(starts) != fmt.Sprint(tt.wantStarts) {
+			t.Errorf("downsample(%v, %d, %v) = %v, %v, want %v, %v", tt.values, tt.width, tt.peak, values, starts, tt.wantValues, tt.wantStarts)
+		}
+	}
+}
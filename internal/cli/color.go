@@ -18,8 +18,40 @@ type Color struct {
 	magenta *color.Color
 }
 
-// NewColor creates a color helper.
+// NewColor creates a color helper with the default theme.
 func NewColor(enabled bool) Color {
+	return NewThemedColor(enabled, "default")
+}
+
+// NewThemedColor creates a color helper. The "bright" theme uses
+// high-intensity colors for dark backgrounds; "mono" drops hues and
+// emphasizes with bold only.
+func NewThemedColor(enabled bool, theme string) Color {
+	switch theme {
+	case "bright":
+		return Color{
+			enabled: enabled,
+			bold:    color.New(color.Bold),
+			cyan:    color.New(color.FgHiCyan),
+			green:   color.New(color.FgHiGreen),
+			yellow:  color.New(color.FgHiYellow),
+			red:     color.New(color.FgHiRed),
+			blue:    color.New(color.FgHiBlue),
+			magenta: color.New(color.FgHiMagenta),
+		}
+	case "mono":
+		bold := color.New(color.Bold)
+		return Color{
+			enabled: enabled,
+			bold:    bold,
+			cyan:    color.New(color.Reset),
+			green:   color.New(color.Reset),
+			yellow:  bold,
+			red:     bold,
+			blue:    color.New(color.Reset),
+			magenta: bold,
+		}
+	}
 	return Color{
 		enabled: enabled,
 		bold:    color.New(color.Bold),
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// CompareCmd compares several locations side by side.
+type CompareCmd struct {
+	Locations     []string `arg:"" name:"location" sep:"none" help:"Two or more location names, coordinates, id:N or @name."`
+	LocationFlags `embed:""`
+	Days          int `help:"Number of forecast days to compare (1-16)." default:"3"`
+}
+
+// comparison is one location of a comparison.
+type comparison struct {
+	Query    string                     `json:"query"`
+	Current  *weathercli.CurrentWeather `json:"current"`
+	Forecast *weathercli.Forecast       `json:"forecast"`
+}
+
+// compareRow is one row of the comparison table. Cells are plain text;
+// when highlight is non-zero the highest (1) or lowest (-1) values are
+// marked.
+type compareRow struct {
+	label     string
+	cells     []string
+	values    []float64
+	ok        []bool // values[i] is present
+	highlight int
+	color     func(string) string
+}
+
+// Run for CompareCmd.
+func (c *CompareCmd) Run(app *App, ctx context.Context) error {
+	if len(c.Locations) < 2 {
+		return usagef("compare needs at least two locations")
+	}
+	if c.Days < 1 || c.Days > 16 {
+		return usagef("days must be between 1 and 16")
+	}
+	if app.verbose {
+		app.renderVerbose("Comparing %d-day forecast for: %s", c.Days, strings.Join(c.Locations, "; "))
+	}
+
+	results := make([]comparison, len(c.Locations))
+	for i, query := range c.Locations {
+		loc, err := app.resolveLocation(ctx, query, c.LocationFlags)
+		if err != nil {
+			return fmt.Errorf("%s: %w", query, err)
+		}
+		current, err := app.client.CurrentByCoords(ctx, loc.Latitude, loc.Longitude, &loc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", query, err)
+		}
+		forecast, err := app.client.ForecastByCoords(ctx, loc.Latitude, loc.Longitude, c.Days, false, &loc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", query, err)
+		}
+		results[i] = comparison{Query: query, Current: current, Forecast: forecast}
+	}
+
+	return app.RenderComparison(results)
+}
+
+// RenderComparison outputs a side-by-side comparison in human or JSON
+// format. The warmest temperatures, driest days and windiest winds are
+// highlighted.
+func (a *App) RenderComparison(results []comparison) error {
+	if a.structured() {
+		return a.renderData(results)
+	}
+
+	n := len(results)
+	units := results[0].Current.Units
+	header := make([]string, n)
+	for i, r := range results {
+		header[i] = r.Current.Location.Name
+	}
+
+	newRow := func(label string, highlight int, color func(string) string) *compareRow {
+		return &compareRow{label: label, cells: make([]string, n), values: make([]float64, n), ok: make([]bool, n), highlight: highlight, color: color}
+	}
+	temp := func(v float64) string { return fmt.Sprintf("%.1f%s", v, units.TemperatureSymbol()) }
+	wind := func(v float64, dir int) string {
+		return fmt.Sprintf("%.1f %s %s", v, units.WindSpeedSymbol(), weathercli.WindDirection(dir))
+	}
+
+	var sections []string
+	var rows [][]*compareRow
+
+	condition := newRow("Condition", 0, nil)
+	nowTemp := newRow("Temperature", 1, a.color.Red)
+	nowWind := newRow("Wind", 1, a.color.Magenta)
+	humidity := newRow("Humidity", 0, nil)
+	for i, r := range results {
+		w := r.Current
+		condition.cells[i] = w.Condition
+		nowTemp.set(i, temp(w.Temperature), w.Temperature)
+		nowWind.set(i, wind(w.WindSpeed, w.WindDirection), w.WindSpeed)
+		humidity.cells[i] = fmt.Sprintf("%d%%", w.Humidity)
+	}
+	sections = append(sections, "Now")
+	rows = append(rows, []*compareRow{condition, nowTemp, nowWind, humidity})
+
+	for d, day := range results[0].Forecast.Daily {
+		condition := newRow("Condition", 0, nil)
+		high := newRow("High", 1, a.color.Red)
+		low := newRow("Low", 1, a.color.Red)
+		precip := newRow("Precipitation", -1, a.color.Green)
+		dayWind := newRow("Wind", 1, a.color.Magenta)
+		for i, r := range results {
+			if d >= len(r.Forecast.Daily) {
+				for _, row := range []*compareRow{condition, high, low, precip, dayWind} {
+					row.cells[i] = "-"
+				}
+				continue
+			}
+			day := r.Forecast.Daily[d]
+			condition.cells[i] = day.Condition
+			high.set(i, temp(day.TempMax), day.TempMax)
+			low.set(i, temp(day.TempMin), day.TempMin)
+			precip.set(i, fmt.Sprintf("%s (%d%%)", formatPrecip(day.Precipitation, units), day.PrecipProb), day.Precipitation)
+			dayWind.set(i, wind(day.WindSpeedMax, day.WindDirection), day.WindSpeedMax)
+		}
+		sections = append(sections, day.Date.Format("Mon Jan 2"))
+		rows = append(rows, []*compareRow{condition, high, low, precip, dayWind})
+	}
+
+	labelWidth := 0
+	widths := make([]int, n)
+	for i, h := range header {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, section := range rows {
+		for _, row := range section {
+			labelWidth = max(labelWidth, utf8.RuneCountInString(row.label)+2)
+			for i, cell := range row.cells {
+				widths[i] = max(widths[i], utf8.RuneCountInString(cell)+1) // room for the marker
+			}
+		}
+	}
+
+	widths[n-1] = 0 // no trailing padding
+	fmt.Fprint(a.out, strings.Repeat(" ", labelWidth))
+	for i, h := range header {
+		fmt.Fprintf(a.out, "  %s", a.color.Bold(pad(h, widths[i])))
+	}
+	fmt.Fprintln(a.out)
+
+	for s, section := range rows {
+		fmt.Fprintln(a.out, a.color.Bold(sections[s]))
+		for _, row := range section {
+			fmt.Fprint(a.out, a.color.Cyan(pad("  "+row.label, labelWidth)))
+			best := row.best()
+			for i, cell := range row.cells {
+				if best[i] {
+					fmt.Fprintf(a.out, "  %s", row.color(pad(cell+"*", widths[i])))
+					continue
+				}
+				fmt.Fprintf(a.out, "  %s", pad(cell, widths[i]))
+			}
+			fmt.Fprintln(a.out)
+		}
+	}
+	fmt.Fprintln(a.out)
+	fmt.Fprintln(a.out, "* warmest, windiest or driest in its row")
+	return nil
+}
+
+func (r *compareRow) set(i int, cell string, value float64) {
+	r.cells[i] = cell
+	r.values[i] = value
+	r.ok[i] = true
+}
+
+// best reports which cells hold the row's highest (or lowest) value. Rows
+// where every value is the same highlight nothing.
+func (r *compareRow) best() []bool {
+	best := make([]bool, len(r.cells))
+	if r.highlight == 0 {
+		return best
+	}
+	found, distinct := false, false
+	var target float64
+	for i, ok := range r.ok {
+		if !ok {
+			continue
+		}
+		v := r.values[i] * float64(r.highlight)
+		if found && v != target {
+			distinct = true
+		}
+		if !found || v > target {
+			target, found = v, true
+		}
+	}
+	if !distinct {
+		return best
+	}
+	for i, ok := range r.ok {
+		best[i] = ok && r.values[i]*float64(r.highlight) == target
+	}
+	return best
+}
+
+// pad left-aligns s in a field of width runes.
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+func testComparison() []comparison {
+	units := weathercli.MetricUnits()
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	place := func(name string, temp, wind float64, humidity int, days ...weathercli.DailyForecast) comparison {
+		loc := weathercli.Location{Name: name}
+		return comparison{
+			Query:    name,
+			Current:  &weathercli.CurrentWeather{Location: loc, Units: units, Condition: "Overcast", Temperature: temp, WindSpeed: wind, WindDirection: 270, Humidity: humidity},
+			Forecast: &weathercli.Forecast{Location: loc, Units: units, Daily: days},
+		}
+	}
+	return []comparison{
+		place("Berlin", 3.5, 12, 81,
+			weathercli.DailyForecast{Date: day(12), Condition: "Slight rain", TempMax: 5, TempMin: -1, Precipitation: 2.4, PrecipProb: 70, WindSpeedMax: 20, WindDirection: 250},
+			weathercli.DailyForecast{Date: day(13), Condition: "Clear sky", TempMax: 2, TempMin: -4, PrecipProb: 0, WindSpeedMax: 9, WindDirection: 0}),
+		place("Lisbon", 14.2, 12, 64,
+			weathercli.DailyForecast{Date: day(12), Condition: "Partly cloudy", TempMax: 16.5, TempMin: 9, PrecipProb: 5, WindSpeedMax: 15, WindDirection: 315}),
+	}
+}
+
+func TestRenderComparison(t *testing.T) {
+	var out strings.Builder
+	app := &App{out: &out, format: formatHuman, color: NewColor(false)}
+	if err := app.RenderComparison(testComparison()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ties (Now: Wind) and rows with a single value (Sat Jan 13) are not
+	// highlighted; locations without the day show "-".
+	want := `                 Berlin          Lisbon
+Now
+  Condition      Overcast        Overcast
+  Temperature    3.5°C           14.2°C*
+  Wind           12.0 km/h W     12.0 km/h W
+  Humidity       81%             64%
+Fri Jan 12
+  Condition      Slight rain     Partly cloudy
+  High           5.0°C           16.5°C*
+  Low            -1.0°C          9.0°C*
+  Precipitation  2.4 mm (70%)    0.0 mm (5%)*
+  Wind           20.0 km/h WSW*  15.0 km/h NW
+Sat Jan 13
+  Condition      Clear sky       -
+  High           2.0°C           -
+  Low            -4.0°C          -
+  Precipitation  0.0 mm (0%)     -
+  Wind           9.0 km/h N      -
+
+* warmest, windiest or driest in its row
+`
+	if got := out.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderComparisonJSON(t *testing.T) {
+	var out strings.Builder
+	app := &App{out: &out, format: formatJSON, color: NewColor(false)}
+	if err := app.RenderComparison(testComparison()); err != nil {
+		t.Fatal(err)
+	}
+	var got []struct {
+		Query    string                    `json:"query"`
+		Current  weathercli.CurrentWeather `json:"current"`
+		Forecast weathercli.Forecast       `json:"forecast"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	if len(got) != 2 || got[0].Query != "Berlin" || got[1].Current.Temperature != 14.2 || len(got[0].Forecast.Daily) != 2 {
+		t.Errorf("JSON = %s", out.String())
+	}
+}
+
+func TestCompareRowBest(t *testing.T) {
+	tests := []struct {
+		highlight int
+		values    []float64
+		ok        []bool
+		want      []bool
+	}{
+		{1, []float64{3, 7, 5}, []bool{true, true, true}, []bool{false, true, false}},
+		{-1, []float64{3, 7, 5}, []bool{true, true, true}, []bool{true, false, false}},
+		{1, []float64{7, 7, 5}, []bool{true, true, true}, []bool{true, true, false}},
+		{1, []float64{4, 4, 4}, []bool{true, true, true}, []bool{false, false, false}},
+		{-1, []float64{0, 0, 2}, []bool{false, true, true}, []bool{false, true, false}},
+		{1, []float64{0, 9, 0}, []bool{false, true, false}, []bool{false, false, false}},
+		{0, []float64{3, 7, 5}, []bool{true, true, true}, []bool{false, false, false}},
+	}
+	for _, tt := range tests {
+		row := &compareRow{cells: make([]string, len(tt.values)), values: tt.values, ok: tt.ok, highlight: tt.highlight}
+		if got := row.best(); !slices.Equal(got, tt.want) {
+			t.Errorf("best(%v, %v, highlight %d) = %v, want %v", tt.values, tt.ok, tt.highlight, got, tt.want)
+		}
+	}
+}
+
+func TestCompareNeedsTwoLocations(t *testing.T) {
+	app := &App{out: io.Discard, format: formatHuman, color: NewColor(false)}
+	err := (&CompareCmd{Locations: []string{"Berlin"}, Days: 3}).Run(app, context.Background())
+	var usage *weathercli.UsageError
+	if !errors.As(err, &usage) {
+		t.Errorf("error = %v, want a usage error", err)
+	}
+}
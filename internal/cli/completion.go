@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// CompletionCmd prints a shell completion script.
+type CompletionCmd struct {
+	Shell string `arg:"" name:"shell" enum:"bash,zsh,fish" help:"Shell to generate completion for (bash, zsh, fish)."`
+}
+
+// completionCommand describes a top-level command for completion scripts.
+type completionCommand struct {
+	name        string
+	help        string
+	flags       []string // long flag names, including global flags
+	subcommands []string
+	location    bool // takes a location argument, so @name applies
+}
+
+// Run for CompletionCmd.
+func (c *CompletionCmd) Run(app *App, kctx *kong.Context) error {
+	commands := completionCommands(kctx.Model.Node)
+	switch c.Shell {
+	case "bash":
+		writeBashCompletion(app.out, commands)
+	case "zsh":
+		fmt.Fprintln(app.out, "#compdef weathercli")
+		fmt.Fprintln(app.out, "autoload -U +X bashcompinit && bashcompinit")
+		writeBashCompletion(app.out, commands)
+	case "fish":
+		writeFishCompletion(app.out, commands)
+	}
+	return nil
+}
+
+// completionCommands collects the visible commands and their flags from
+// the kong model, so new commands and flags complete without changes here.
+func completionCommands(root *kong.Node) []completionCommand {
+	var commands []completionCommand
+	for _, node := range root.Children {
+		if node.Hidden || node.Type != kong.CommandNode {
+			continue
+		}
+		cmd := completionCommand{name: node.Name, help: node.Help}
+
+		seen := map[string]bool{}
+		_ = kong.Visit(node, func(v kong.Visitable, next kong.Next) error {
+			n, ok := v.(*kong.Node)
+			if !ok {
+				return next(nil)
+			}
+			for _, group := range n.AllFlags(true) {
+				for _, f := range group {
+					if !seen[f.Name] {
+						seen[f.Name] = true
+						cmd.flags = append(cmd.flags, "--"+f.Name)
+					}
+				}
+			}
+			if n != node && n.Type == kong.CommandNode && n.Parent == node && !n.Hidden {
+				cmd.subcommands = append(cmd.subcommands, n.Name)
+			}
+			for _, p := range n.Positional {
+				if p.Name == "location" || p.Name == "query" {
+					cmd.location = true
+				}
+			}
+			return next(nil)
+		})
+		sort.Strings(cmd.flags)
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// savedNamesCommand lists saved location names for completion.
+const savedNamesCommand = "weathercli locations list --names 2>/dev/null"
+
+func writeBashCompletion(w io.Writer, commands []completionCommand) {
+	names := make([]string, len(commands))
+	for i, cmd := range commands {
+		names[i] = cmd.name
+	}
+
+	fmt.Fprintf(w, `# weathercli completion for bash; add to ~/.bashrc:
+#   source <(weathercli completion bash)
+_weathercli() {
+    local cur=${COMP_WORDS[COMP_CWORD]} cmd=${COMP_WORDS[1]} sub=${COMP_WORDS[2]}
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W %q -- "$cur"))
+        return
+    fi
+    local flags="" subcommands="" location=""
+    case $cmd in
+`, strings.Join(names, " "))
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "    %s) flags=%q subcommands=%q location=%q ;;\n",
+			cmd.name, strings.Join(cmd.flags, " "), strings.Join(cmd.subcommands, " "), boolWord(cmd.location))
+	}
+	fmt.Fprintf(w, `    esac
+    if [[ $cur == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+    elif [[ $cur == @* && -n $location ]]; then
+        COMPREPLY=($(compgen -W "$(%s | sed 's/^/@/')" -- "$cur"))
+    elif [[ $COMP_CWORD -eq 2 && -n $subcommands ]]; then
+        COMPREPLY=($(compgen -W "$subcommands" -- "$cur"))
+    elif [[ $cmd == locations && $COMP_CWORD -eq 3 && $sub =~ ^(remove|rm|rename)$ ]]; then
+        COMPREPLY=($(compgen -W "$(%s)" -- "$cur"))
+    fi
+}
+complete -F _weathercli weathercli
+`, savedNamesCommand, savedNamesCommand)
+}
+
+func writeFishCompletion(w io.Writer, commands []completionCommand) {
+	fmt.Fprintln(w, "# weathercli completion for fish; save as ~/.config/fish/completions/weathercli.fish")
+	fmt.Fprintln(w, "complete -c weathercli -f")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "complete -c weathercli -n __fish_use_subcommand -a %s -d %s\n", cmd.name, fishQuote(cmd.help))
+	}
+	for _, cmd := range commands {
+		cond := fishQuote("__fish_seen_subcommand_from " + cmd.name)
+		for _, flag := range cmd.flags {
+			fmt.Fprintf(w, "complete -c weathercli -n %s -l %s\n", cond, strings.TrimPrefix(flag, "--"))
+		}
+		if len(cmd.subcommands) > 0 {
+			fmt.Fprintf(w, "complete -c weathercli -n %s -a %s\n", cond, fishQuote(strings.Join(cmd.subcommands, " ")))
+		}
+		if cmd.location {
+			fmt.Fprintf(w, "complete -c weathercli -n %s -a %s\n", cond, fishQuote("("+savedNamesCommand+" | string replace -r '^' '@')"))
+		}
+	}
+	fmt.Fprintf(w, "complete -c weathercli -n %s -a %s\n",
+		fishQuote("__fish_seen_subcommand_from remove rm rename"), fishQuote("("+savedNamesCommand+")"))
+}
+
+// fishQuote single-quotes s for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func boolWord(b bool) string {
+	if b {
+		return "1"
+	}
+	return ""
+}
@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/pjtf93/weathercli"
+)
+
+// ConfigCmd inspects and edits the config file.
+type ConfigCmd struct {
+	Show ConfigShowCmd `cmd:"" default:"1" help:"Show effective settings and where they come from."`
+	Get  ConfigGetCmd  `cmd:"" help:"Print the effective value of a setting."`
+	Set  ConfigSetCmd  `cmd:"" help:"Set a value in the config file."`
+	Path ConfigPathCmd `cmd:"" help:"Print the config file path."`
+}
+
+// ConfigShowCmd shows effective settings.
+type ConfigShowCmd struct{}
+
+// ConfigGetCmd prints one setting.
+type ConfigGetCmd struct {
+	Key string `arg:"" name:"key" help:"Setting, e.g. 'units' or 'forecast.days'."`
+}
+
+// ConfigSetCmd writes one setting.
+type ConfigSetCmd struct {
+	Key   string `arg:"" name:"key" help:"Setting, e.g. 'units' or 'forecast.days'."`
+	Value string `arg:"" name:"value" help:"Value to store."`
+}
+
+// ConfigPathCmd prints the config file path.
+type ConfigPathCmd struct{}
+
+// configLocationKey holds the default location for commands that take one.
+const configLocationKey = "location"
+
+// configResolver resolves flags from the config file. Keys are flag names;
+// a "[command]" section sets flags for that command only, and top-level
+// keys apply to every command with the flag. Values from the command line
+// or a flag's environment variable take precedence.
+type configResolver struct {
+	path string
+	cfg  *weathercli.Config
+}
+
+// Validate rejects keys that match no flag.
+func (r configResolver) Validate(app *kong.Application) error {
+	for _, key := range r.cfg.Keys() {
+		if key == configLocationKey {
+			continue
+		}
+		if _, err := configFlag(app, key); err != nil {
+			return fmt.Errorf("%s: %w", r.path, err)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the config value for flag, if any.
+func (r configResolver) Resolve(_ *kong.Context, parent *kong.Path, flag *kong.Flag) (any, error) {
+	for _, env := range flag.Envs {
+		if _, ok := os.LookupEnv(env); ok {
+			return nil, nil
+		}
+	}
+	if parent.Command != nil {
+		if v, ok := r.cfg.Get(commandKey(parent.Command) + "." + flag.Name); ok {
+			return v, nil
+		}
+	}
+	if v, ok := r.cfg.Get(flag.Name); ok {
+		return v, nil
+	}
+	return nil, nil
+}
+
+// commandKey returns the config section of a command, e.g. "forecast" or
+// "locations.add".
+func commandKey(node *kong.Node) string {
+	var parts []string
+	for n := node; n != nil && n.Type == kong.CommandNode; n = n.Parent {
+		parts = append([]string{n.Name}, parts...)
+	}
+	return strings.Join(parts, ".")
+}
+
+// configFlag finds the flag a config key sets: "key" names a global flag
+// or a flag of any command, "command.key" a flag of that command.
+func configFlag(app *kong.Application, key string) (*kong.Flag, error) {
+	key = strings.ReplaceAll(strings.ToLower(key), "_", "-")
+	section, name := "", key
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		section, name = key[:i], key[i+1:]
+	}
+
+	var found *kong.Flag
+	_ = kong.Visit(app.Node, func(v kong.Visitable, next kong.Next) error {
+		n, ok := v.(*kong.Node)
+		if !ok || found != nil {
+			return next(nil)
+		}
+		if section == "" || (n.Type == kong.CommandNode && commandKey(n) == section) {
+			for _, f := range n.Flags {
+				if f.Name == name && !f.Hidden && !isBuiltinFlag(f) {
+					found = f
+				}
+			}
+		}
+		return next(nil)
+	})
+	if found == nil {
+		return nil, fmt.Errorf("unknown config key %q", key)
+	}
+	return found, nil
+}
+
+// isBuiltinFlag reports flags that make no sense in a config file.
+func isBuiltinFlag(f *kong.Flag) bool {
+	return f.Name == "help" || f.Name == "version"
+}
+
+// checkConfigValue validates value for the flag's type and enum.
+func checkConfigValue(f *kong.Flag, value string) error {
+	var err error
+	switch {
+	case f.Target.Type() == reflect.TypeOf(time.Duration(0)):
+		_, err = time.ParseDuration(value)
+	case f.Target.Kind() == reflect.Bool:
+		_, err = strconv.ParseBool(value)
+	case f.Target.Kind() == reflect.Int:
+		_, err = strconv.Atoi(value)
+	case f.Target.Kind() == reflect.Slice:
+		return fmt.Errorf("--%s takes a list; config values must be single values", f.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for %s", value, f.Name)
+	}
+	if f.Enum != "" && !f.EnumMap()[value] {
+		return fmt.Errorf("invalid value %q for %s (want one of %s)", value, f.Name, strings.Join(f.EnumSlice(), ", "))
+	}
+	return nil
+}
+
+// configSetting is one effective setting.
+type configSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // "flag", "env", "config" or "default"
+}
+
+// effectiveSettings lists the global flags, the default location and any
+// command sections in the config file, with their values and sources.
+func (a *App) effectiveSettings(kctx *kong.Context) []configSetting {
+	onCommandLine := map[*kong.Flag]bool{}
+	for _, p := range kctx.Path {
+		if p.Flag != nil && !p.Resolved {
+			onCommandLine[p.Flag] = true
+		}
+	}
+
+	location, source := a.defaultLocation()
+	settings := []configSetting{{Key: configLocationKey, Value: location, Source: source}}
+	for _, f := range kctx.Model.Node.Flags {
+		if f.Hidden || isBuiltinFlag(f) {
+			continue
+		}
+		s := configSetting{Key: f.Name, Value: fmt.Sprint(kctx.FlagValue(f)), Source: "default"}
+		switch {
+		case onCommandLine[f]:
+			s.Source = "flag"
+		case envSet(f.Envs):
+			s.Source = "env"
+		default:
+			if _, ok := a.config.Get(f.Name); ok {
+				s.Source = "config"
+			}
+		}
+		settings = append(settings, s)
+	}
+	for _, key := range a.config.Keys() {
+		if strings.Contains(key, ".") {
+			v, _ := a.config.Get(key)
+			settings = append(settings, configSetting{Key: key, Value: v, Source: "config"})
+		}
+	}
+	return settings
+}
+
+// defaultLocation returns the location used when a command is given none.
+func (a *App) defaultLocation() (string, string) {
+	if v, ok := os.LookupEnv("WEATHER_LOCATION"); ok {
+		return v, "env"
+	}
+	if v, ok := a.config.Get(configLocationKey); ok {
+		return v, "config"
+	}
+	return "", "default"
+}
+
+func envSet(envs []string) bool {
+	for _, env := range envs {
+		if _, ok := os.LookupEnv(env); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Run for ConfigShowCmd.
+func (c *ConfigShowCmd) Run(app *App, kctx *kong.Context) error {
+	settings := app.effectiveSettings(kctx)
+	if app.structured() {
+		return app.renderData(settings)
+	}
+
+	keyWidth, valueWidth := 0, 0
+	for _, s := range settings {
+		keyWidth = max(keyWidth, len(s.Key))
+		valueWidth = max(valueWidth, len(strconv.Quote(s.Value)))
+	}
+
+	path := app.configPath
+	if _, err := os.Stat(path); err != nil {
+		path += " (not created yet)"
+	}
+	fmt.Fprintf(app.out, "%s %s\n\n", app.color.Cyan("Config file:"), path)
+	for _, s := range settings {
+		fmt.Fprintf(app.out, "%s = %-*s  %s\n",
+			app.color.Bold(fmt.Sprintf("%-*s", keyWidth, s.Key)), valueWidth, strconv.Quote(s.Value), app.color.Cyan("# "+s.Source))
+	}
+	return nil
+}
+
+// Run for ConfigGetCmd.
+func (c *ConfigGetCmd) Run(app *App, kctx *kong.Context) error {
+	key := strings.ReplaceAll(strings.ToLower(c.Key), "_", "-")
+	for _, s := range app.effectiveSettings(kctx) {
+		if strings.ReplaceAll(s.Key, "_", "-") == key {
+			fmt.Fprintln(app.out, s.Value)
+			return nil
+		}
+	}
+
+	f, err := configFlag(kctx.Model, key)
+	if err != nil {
+		return err
+	}
+	if v, ok := app.config.Get(key); ok {
+		fmt.Fprintln(app.out, v)
+		return nil
+	}
+	fmt.Fprintln(app.out, f.Default)
+	return nil
+}
+
+// Run for ConfigSetCmd.
+func (c *ConfigSetCmd) Run(app *App, kctx *kong.Context) error {
+	if app.configPath == "" {
+		return fmt.Errorf("no config directory")
+	}
+	if strings.ToLower(c.Key) != configLocationKey {
+		f, err := configFlag(kctx.Model, c.Key)
+		if err != nil {
+			return err
+		}
+		if err := checkConfigValue(f, c.Value); err != nil {
+			return err
+		}
+	}
+
+	app.config.Set(c.Key, c.Value)
+	if err := app.config.Save(app.configPath); err != nil {
+		return err
+	}
+	if app.verbose {
+		app.renderVerbose("Wrote %s", app.configPath)
+	}
+	return nil
+}
+
+// Run for ConfigPathCmd.
+func (c *ConfigPathCmd) Run(app *App) error {
+	fmt.Fprintln(app.out, app.configPath)
+	return nil
+}
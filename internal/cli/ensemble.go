@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// runEnsemble fetches and renders an ensemble forecast for --ensemble.
+func (c *ForecastCmd) runEnsemble(ctx context.Context, app *App, loc weathercli.Location, days int) error {
+	var models []string
+	for _, m := range c.Models {
+		for _, model := range strings.Split(m, ",") {
+			if model = strings.TrimSpace(model); model != "" {
+				models = append(models, model)
+			}
+		}
+	}
+	if app.verbose {
+		app.renderVerbose("Fetching ensemble forecast (%s)", strings.Join(models, ", "))
+	}
+
+	ensemble, err := app.client.Ensemble(ctx, loc.Latitude, loc.Longitude, days, c.Hourly, &loc, weathercli.EnsembleOptions{
+		Models:          models,
+		PrecipThreshold: c.PrecipThreshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.Hourly && len(ensemble.Hourly) > c.Hours {
+		ensemble.Hourly = ensemble.Hourly[:c.Hours]
+	}
+
+	return app.RenderEnsemble(ensemble)
+}
+
+// RenderEnsemble outputs an ensemble forecast: each value as its mean with
+// the 10th-90th percentile range and the extremes across members.
+func (a *App) RenderEnsemble(e *weathercli.EnsembleForecast) error {
+	if a.structured() {
+		return a.renderData(e)
+	}
+
+	units := e.Units
+	temp := spreadFormat{"%.1f", units.TemperatureSymbol()}
+	precip := spreadFormat{"%.1f", " mm"}
+	if units.Precipitation == weathercli.Inches {
+		precip = spreadFormat{"%.2f", " in"}
+	}
+	wind := spreadFormat{"%.1f", " " + units.WindSpeedSymbol()}
+	chance := func(prob int) string {
+		return fmt.Sprintf("%d%% chance of more than %s", prob, formatPrecip(e.PrecipThreshold, units))
+	}
+
+	fmt.Fprintf(a.out, "%s\n", a.color.Bold(locationLabel(e.Location)))
+	fmt.Fprintf(a.out, "%s\n", a.color.Cyan(fmt.Sprintf("Ensemble of %d members (%s): mean (10th–90th percentile, extremes)",
+		e.Members, strings.Join(e.Models, ", "))))
+	a.renderStale(e.Stale, e.CachedAt)
+	fmt.Fprintln(a.out)
+
+	for _, day := range e.Daily {
+		fmt.Fprintf(a.out, "%s\n", a.color.Bold(day.Date.Format("Mon Jan 2")))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("High:"), a.formatTempSpread(day.TempMax, units, temp))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Low:"), a.formatTempSpread(day.TempMin, units, temp))
+		fmt.Fprintf(a.out, "  %s %s, %s\n", a.color.Cyan("Precipitation:"), formatSpread(day.Precipitation, precip), chance(day.PrecipProb))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Wind:"), formatSpread(day.WindSpeedMax, wind))
+		fmt.Fprintln(a.out)
+	}
+
+	for _, hour := range e.Hourly {
+		fmt.Fprintf(a.out, "%s\n", a.color.Bold(hour.Time.Format("Mon Jan 2 15:04")))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Temperature:"), a.formatTempSpread(hour.Temperature, units, temp))
+		fmt.Fprintf(a.out, "  %s %s, %s\n", a.color.Cyan("Precipitation:"), formatSpread(hour.Precipitation, precip), chance(hour.PrecipProb))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Wind:"), formatSpread(hour.WindSpeed, wind))
+		fmt.Fprintln(a.out)
+	}
+
+	return nil
+}
+
+// spreadFormat is how formatSpread prints a quantity: a fmt verb for the
+// numbers and the unit that follows them.
+type spreadFormat struct {
+	verb, unit string
+}
+
+func (f spreadFormat) number(v float64) string { return fmt.Sprintf(f.verb, v) }
+
+// formatSpread formats stats as "mean (p10–p90, extremes min–max)".
+func formatSpread(s weathercli.EnsembleStats, f spreadFormat) string {
+	return fmt.Sprintf("%s%s (%s–%s%s, extremes %s–%s%s)", f.number(s.Mean), f.unit,
+		f.number(s.P10), f.number(s.P90), f.unit, f.number(s.Min), f.number(s.Max), f.unit)
+}
+
+// formatTempSpread is formatSpread with the mean colored like formatTemp.
+func (a *App) formatTempSpread(s weathercli.EnsembleStats, units weathercli.Units, f spreadFormat) string {
+	mean := f.number(s.Mean) + f.unit
+	return colorTemp(mean, s.Mean, units, a.color) + strings.TrimPrefix(formatSpread(s, f), mean)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// ExporterCmd exposes current weather as Prometheus metrics.
+type ExporterCmd struct {
+	Locations []string      `name:"locations" sep:"none" required:"" placeholder:"LOC,..." help:"Comma-separated locations to export (e.g. @home,@office or 52.52,13.41). Repeatable."`
+	Interval  time.Duration `help:"How often to refresh every location." default:"5m"`
+	Addr      string        `help:"Address to serve /metrics on." default:":9101"`
+}
+
+// Run for ExporterCmd.
+func (c *ExporterCmd) Run(app *App, ctx context.Context) error {
+	if c.Interval < minWatchInterval {
+		return usagef("--interval must be at least %s", minWatchInterval)
+	}
+	// The exporter runs unattended, so ambiguous names take the best match.
+	app.interactive = false
+
+	var locs []weathercli.Location
+	for _, query := range splitLocations(c.Locations) {
+		loc, err := app.resolveLocation(ctx, query, LocationFlags{})
+		if err == nil {
+			// Labels must not change between scrapes, so resolve the
+			// timezone label of coordinates up front.
+			loc, err = app.resolveTimezone(ctx, loc)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", query, err)
+		}
+		if app.verbose {
+			app.renderVerbose("Exporting %s (%.4f, %.4f)", locationLabel(loc), loc.Latitude, loc.Longitude)
+		}
+		locs = append(locs, loc)
+	}
+
+	exporter := weathercli.NewExporter(app.client, locs, weathercli.ExporterOptions{Interval: c.Interval})
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", exporter)
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `weathercli exporter: metrics at /metrics`)
+	})
+
+	ln, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	fmt.Fprintf(app.err, "Exporting %d locations on http://%s/metrics every %s (Ctrl-C to stop)\n", len(locs), ln.Addr(), c.Interval)
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ln) }()
+	go func() { _ = exporter.Run(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-done; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// splitLocations splits comma-separated location lists, keeping
+// "lat,lon" pairs together.
+func splitLocations(values []string) []string {
+	var out []string
+	for _, v := range values {
+		parts := strings.Split(v, ",")
+		for i := 0; i < len(parts); i++ {
+			part := strings.TrimSpace(parts[i])
+			if i+1 < len(parts) && isNumber(part) && isNumber(strings.TrimSpace(parts[i+1])) {
+				part += "," + strings.TrimSpace(parts[i+1])
+				i++
+			}
+			if part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
@@ -0,0 +1,413 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// Output formats for --format.
+const (
+	formatHuman  = "human"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+	formatTSV    = "tsv"
+	formatYAML   = "yaml"
+)
+
+// outputFormat returns the --format value, applying the --json and
+// --ndjson aliases and --template.
+func outputFormat(g GlobalOptions) (string, error) {
+	alias := ""
+	switch {
+	case g.JSON && g.NDJSON:
+		return "", fmt.Errorf("--json and --ndjson cannot be combined")
+	case g.JSON:
+		alias = formatJSON
+	case g.NDJSON:
+		alias = formatNDJSON
+	case g.Template != "" || g.TemplateFile != "":
+		if g.Format != formatHuman {
+			return "", fmt.Errorf("--template conflicts with --format %s", g.Format)
+		}
+		return formatTemplate, nil
+	default:
+		return g.Format, nil
+	}
+	if g.Template != "" || g.TemplateFile != "" {
+		return "", fmt.Errorf("--template conflicts with --%s", alias)
+	}
+	if g.Format != formatHuman && g.Format != alias {
+		return "", fmt.Errorf("--%s conflicts with --format %s", alias, g.Format)
+	}
+	return alias, nil
+}
+
+// jsonErrors reports whether errors are written as JSON: with --json or
+// --ndjson, or --format json or ndjson.
+func jsonErrors(format string) bool {
+	return format == formatJSON || format == formatNDJSON
+}
+
+// wantsJSON reports whether the flags ask for JSON output, for errors
+// found before the output format is settled.
+func (g GlobalOptions) wantsJSON() bool {
+	return g.JSON || g.NDJSON || jsonErrors(g.Format)
+}
+
+// structured reports whether output is for programs rather than people.
+func (a *App) structured() bool {
+	return a.format != formatHuman
+}
+
+// renderData outputs v in the selected machine-readable format:
+//
+//   - json: one JSON document
+//   - ndjson: one JSON object per record (series entries, list items)
+//   - csv, tsv: a header row and one row per record, for types with column
+//     definitions
+//   - yaml: v's JSON fields as YAML
+//   - template: the --template executed against v
+//
+// Repeated calls (--watch) write the CSV header once and separate YAML
+// documents with "---".
+func (a *App) renderData(v any) error {
+	defer func() { a.emitted = true }()
+
+	switch a.format {
+	case formatNDJSON:
+		enc := json.NewEncoder(a.out)
+		for _, r := range records(v) {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatCSV, formatTSV:
+		t, err := tableOf(v)
+		if err != nil {
+			return fmt.Errorf("--format %s: %w", a.format, err)
+		}
+		w := csv.NewWriter(a.out)
+		if a.format == formatTSV {
+			w.Comma = '\t'
+		}
+		if !a.emitted {
+			_ = w.Write(t.header)
+		}
+		_ = w.WriteAll(t.rows)
+		return w.Error()
+	case formatTemplate:
+		return a.renderTemplate(v)
+	case formatYAML:
+		if a.emitted {
+			fmt.Fprintln(a.out, "---")
+		}
+		return writeYAML(a.out, v)
+	}
+	return json.NewEncoder(a.out).Encode(v)
+}
+
+// records splits v into NDJSON records. Series entries carry their
+// location's name.
+func records(v any) []any {
+	var out []any
+	switch v := v.(type) {
+	case *weathercli.Forecast:
+		for _, d := range v.Daily {
+			out = append(out, struct {
+				Location string `json:"location"`
+				weathercli.DailyForecast
+			}{v.Location.Name, d})
+		}
+		for _, h := range v.Hourly {
+			out = append(out, struct {
+				Location string `json:"location"`
+				weathercli.HourlyForecast
+			}{v.Location.Name, h})
+		}
+	case *weathercli.EnsembleForecast:
+		for _, d := range v.Daily {
+			out = append(out, struct {
+				Location string `json:"location"`
+				weathercli.EnsembleDay
+			}{v.Location.Name, d})
+		}
+		for _, h := range v.Hourly {
+			out = append(out, struct {
+				Location string `json:"location"`
+				weathercli.EnsembleHour
+			}{v.Location.Name, h})
+		}
+	case []weathercli.Location:
+		for _, loc := range v {
+			out = append(out, loc)
+		}
+	case weathercli.SavedLocations:
+		for _, name := range v.Names() {
+			out = append(out, struct {
+				Name     string              `json:"name"`
+				Location weathercli.Location `json:"location"`
+			}{name, v[name]})
+		}
+	case []batchResult:
+		for _, r := range v {
+			out = append(out, r)
+		}
+	case []comparison:
+		for _, c := range v {
+			out = append(out, c)
+		}
+	case []configSetting:
+		for _, s := range v {
+			out = append(out, s)
+		}
+	case chartSeries:
+		for _, p := range v.Points {
+			out = append(out, struct {
+				Location string `json:"location"`
+				Metric   string `json:"metric"`
+				Unit     string `json:"unit"`
+				chartPoint
+			}{v.Location.Name, v.Metric, v.Unit, p})
+		}
+	default:
+		out = append(out, v)
+	}
+	return out
+}
+
+// column is one CSV/TSV column of a record type.
+type column[T any] struct {
+	name  string
+	value func(T) string
+}
+
+// table is tabular output: a header and rows of cells.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func tableFrom[T any](columns []column[T], items ...T) table {
+	t := table{header: make([]string, len(columns))}
+	for i, c := range columns {
+		t.header[i] = c.name
+	}
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = c.value(item)
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+// prefixed adds a leading column.
+func (t table) prefixed(name string, values ...string) table {
+	out := table{header: append([]string{name}, t.header...)}
+	for i, row := range t.rows {
+		out.rows = append(out.rows, append([]string{values[min(i, len(values)-1)]}, row...))
+	}
+	return out
+}
+
+// tableOf returns the rows of v for CSV/TSV output.
+func tableOf(v any) (table, error) {
+	switch v := v.(type) {
+	case *weathercli.CurrentWeather:
+		return tableFrom(currentColumns, v), nil
+	case *weathercli.Forecast:
+		t := tableFrom(dailyColumns, v.Daily...)
+		if len(v.Hourly) > 0 {
+			t = tableFrom(hourlyColumns, v.Hourly...)
+		}
+		return t.prefixed("location", v.Location.Name), nil
+	case *weathercli.EnsembleForecast:
+		t := tableFrom(ensembleDailyColumns, v.Daily...)
+		if len(v.Hourly) > 0 {
+			t = tableFrom(ensembleHourlyColumns, v.Hourly...)
+		}
+		return t.prefixed("location", v.Location.Name), nil
+	case []weathercli.Location:
+		return tableFrom(locationColumns, v...), nil
+	case weathercli.SavedLocations:
+		names := v.Names()
+		locs := make([]weathercli.Location, len(names))
+		for i, name := range names {
+			locs[i] = v[name]
+		}
+		t := tableFrom(locationColumns, locs...)
+		t.header[0] = "location"
+		return t.prefixed("name", names...), nil
+	case []configSetting:
+		return tableFrom(configColumns, v...), nil
+	case []batchResult:
+		return batchTable(v)
+	case chartSeries:
+		return tableFrom([]column[chartPoint]{
+			{"time", func(p chartPoint) string { return formatTime(p.Time) }},
+			{v.Metric, func(p chartPoint) string { return formatFloat(p.Value) }},
+			{"unit", func(chartPoint) string { return v.Unit }},
+		}, v.Points...).prefixed("location", v.Location.Name), nil
+	}
+	return table{}, fmt.Errorf("not supported by this command (use json, ndjson or yaml)")
+}
+
+// batchTable combines the tables of multi-location results, with the query
+// first and any error last.
+func batchTable(results []batchResult) (table, error) {
+	var header []string
+	var parts []table
+	for _, r := range results {
+		var t table
+		if r.Result != nil {
+			var err error
+			if t, err = tableOf(r.Result); err != nil {
+				return table{}, err
+			}
+			if header == nil {
+				header = t.header
+			}
+		}
+		parts = append(parts, t)
+	}
+
+	out := table{header: append(append([]string{"query"}, header...), "error")}
+	for i, r := range results {
+		if r.Error != nil {
+			row := make([]string, len(out.header))
+			row[0], row[len(row)-1] = r.Query, r.Error.Message
+			out.rows = append(out.rows, row)
+			continue
+		}
+		for _, cells := range parts[i].rows {
+			out.rows = append(out.rows, append(append([]string{r.Query}, cells...), ""))
+		}
+	}
+	return out, nil
+}
+
+func formatFloat(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+var locationColumns = []column[weathercli.Location]{
+	{"name", func(l weathercli.Location) string { return l.Name }},
+	{"latitude", func(l weathercli.Location) string { return formatFloat(l.Latitude) }},
+	{"longitude", func(l weathercli.Location) string { return formatFloat(l.Longitude) }},
+	{"elevation", func(l weathercli.Location) string { return formatFloat(l.Elevation) }},
+	{"country", func(l weathercli.Location) string { return l.Country }},
+	{"country_code", func(l weathercli.Location) string { return l.CountryCode }},
+	{"admin1", func(l weathercli.Location) string { return l.Admin1 }},
+	{"admin2", func(l weathercli.Location) string { return l.Admin2 }},
+	{"timezone", func(l weathercli.Location) string { return l.Timezone }},
+	{"population", func(l weathercli.Location) string { return strconv.Itoa(l.Population) }},
+	{"id", func(l weathercli.Location) string { return strconv.FormatInt(l.ID, 10) }},
+}
+
+var currentColumns = []column[*weathercli.CurrentWeather]{
+	{"location", func(w *weathercli.CurrentWeather) string { return w.Location.Name }},
+	{"latitude", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Location.Latitude) }},
+	{"longitude", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Location.Longitude) }},
+	{"time", func(w *weathercli.CurrentWeather) string { return formatTime(w.Time) }},
+	{"temperature", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Temperature) }},
+	{"apparent", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Apparent) }},
+	{"humidity", func(w *weathercli.CurrentWeather) string { return strconv.Itoa(w.Humidity) }},
+	{"precipitation", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Precipitation) }},
+	{"rain", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Rain) }},
+	{"snowfall", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Snowfall) }},
+	{"wind_speed", func(w *weathercli.CurrentWeather) string { return formatFloat(w.WindSpeed) }},
+	{"wind_direction", func(w *weathercli.CurrentWeather) string { return strconv.Itoa(w.WindDirection) }},
+	{"pressure", func(w *weathercli.CurrentWeather) string { return formatFloat(w.Pressure) }},
+	{"cloud_cover", func(w *weathercli.CurrentWeather) string { return strconv.Itoa(w.CloudCover) }},
+	{"uv_index", func(w *weathercli.CurrentWeather) string { return formatFloat(w.UVIndex) }},
+	{"weather_code", func(w *weathercli.CurrentWeather) string { return strconv.Itoa(w.WeatherCode) }},
+	{"condition", func(w *weathercli.CurrentWeather) string { return w.Condition }},
+	{"stale", func(w *weathercli.CurrentWeather) string { return strconv.FormatBool(w.Stale) }},
+}
+
+var dailyColumns = []column[weathercli.DailyForecast]{
+	{"date", func(d weathercli.DailyForecast) string { return d.Date.Format("2006-01-02") }},
+	{"temp_max", func(d weathercli.DailyForecast) string { return formatFloat(d.TempMax) }},
+	{"temp_min", func(d weathercli.DailyForecast) string { return formatFloat(d.TempMin) }},
+	{"apparent_max", func(d weathercli.DailyForecast) string { return formatFloat(d.ApparentMax) }},
+	{"apparent_min", func(d weathercli.DailyForecast) string { return formatFloat(d.ApparentMin) }},
+	{"precipitation", func(d weathercli.DailyForecast) string { return formatFloat(d.Precipitation) }},
+	{"rain", func(d weathercli.DailyForecast) string { return formatFloat(d.Rain) }},
+	{"snowfall", func(d weathercli.DailyForecast) string { return formatFloat(d.Snowfall) }},
+	{"precip_prob", func(d weathercli.DailyForecast) string { return strconv.Itoa(d.PrecipProb) }},
+	{"wind_speed_max", func(d weathercli.DailyForecast) string { return formatFloat(d.WindSpeedMax) }},
+	{"wind_direction", func(d weathercli.DailyForecast) string { return strconv.Itoa(d.WindDirection) }},
+	{"uv_index_max", func(d weathercli.DailyForecast) string { return formatFloat(d.UVIndexMax) }},
+	{"sunrise", func(d weathercli.DailyForecast) string { return formatTime(d.Sunrise) }},
+	{"sunset", func(d weathercli.DailyForecast) string { return formatTime(d.Sunset) }},
+	{"weather_code", func(d weathercli.DailyForecast) string { return strconv.Itoa(d.WeatherCode) }},
+	{"condition", func(d weathercli.DailyForecast) string { return d.Condition }},
+}
+
+var hourlyColumns = []column[weathercli.HourlyForecast]{
+	{"time", func(h weathercli.HourlyForecast) string { return formatTime(h.Time) }},
+	{"temperature", func(h weathercli.HourlyForecast) string { return formatFloat(h.Temperature) }},
+	{"apparent", func(h weathercli.HourlyForecast) string { return formatFloat(h.Apparent) }},
+	{"humidity", func(h weathercli.HourlyForecast) string { return strconv.Itoa(h.Humidity) }},
+	{"precip_prob", func(h weathercli.HourlyForecast) string { return strconv.Itoa(h.PrecipProb) }},
+	{"precipitation", func(h weathercli.HourlyForecast) string { return formatFloat(h.Precipitation) }},
+	{"rain", func(h weathercli.HourlyForecast) string { return formatFloat(h.Rain) }},
+	{"snowfall", func(h weathercli.HourlyForecast) string { return formatFloat(h.Snowfall) }},
+	{"wind_speed", func(h weathercli.HourlyForecast) string { return formatFloat(h.WindSpeed) }},
+	{"wind_direction", func(h weathercli.HourlyForecast) string { return strconv.Itoa(h.WindDirection) }},
+	{"pressure", func(h weathercli.HourlyForecast) string { return formatFloat(h.Pressure) }},
+	{"cloud_cover", func(h weathercli.HourlyForecast) string { return strconv.Itoa(h.CloudCover) }},
+	{"visibility", func(h weathercli.HourlyForecast) string { return formatFloat(h.Visibility) }},
+	{"uv_index", func(h weathercli.HourlyForecast) string { return formatFloat(h.UVIndex) }},
+	{"weather_code", func(h weathercli.HourlyForecast) string { return strconv.Itoa(h.WeatherCode) }},
+	{"condition", func(h weathercli.HourlyForecast) string { return h.Condition }},
+}
+
+var ensembleDailyColumns = slices.Concat(
+	[]column[weathercli.EnsembleDay]{{"date", func(d weathercli.EnsembleDay) string { return d.Date.Format("2006-01-02") }}},
+	statsColumns("temp_max", func(d weathercli.EnsembleDay) weathercli.EnsembleStats { return d.TempMax }),
+	statsColumns("temp_min", func(d weathercli.EnsembleDay) weathercli.EnsembleStats { return d.TempMin }),
+	statsColumns("precipitation", func(d weathercli.EnsembleDay) weathercli.EnsembleStats { return d.Precipitation }),
+	[]column[weathercli.EnsembleDay]{{"precip_prob", func(d weathercli.EnsembleDay) string { return strconv.Itoa(d.PrecipProb) }}},
+	statsColumns("wind_speed_max", func(d weathercli.EnsembleDay) weathercli.EnsembleStats { return d.WindSpeedMax }),
+)
+
+var ensembleHourlyColumns = slices.Concat(
+	[]column[weathercli.EnsembleHour]{{"time", func(h weathercli.EnsembleHour) string { return formatTime(h.Time) }}},
+	statsColumns("temperature", func(h weathercli.EnsembleHour) weathercli.EnsembleStats { return h.Temperature }),
+	statsColumns("precipitation", func(h weathercli.EnsembleHour) weathercli.EnsembleStats { return h.Precipitation }),
+	[]column[weathercli.EnsembleHour]{{"precip_prob", func(h weathercli.EnsembleHour) string { return strconv.Itoa(h.PrecipProb) }}},
+	statsColumns("wind_speed", func(h weathercli.EnsembleHour) weathercli.EnsembleStats { return h.WindSpeed }),
+)
+
+// statsColumns returns name_mean, name_min, name_p10, name_p90 and
+// name_max columns.
+func statsColumns[T any](name string, stats func(T) weathercli.EnsembleStats) []column[T] {
+	return []column[T]{
+		{name + "_mean", func(v T) string { return formatFloat(stats(v).Mean) }},
+		{name + "_min", func(v T) string { return formatFloat(stats(v).Min) }},
+		{name + "_p10", func(v T) string { return formatFloat(stats(v).P10) }},
+		{name + "_p90", func(v T) string { return formatFloat(stats(v).P90) }},
+		{name + "_max", func(v T) string { return formatFloat(stats(v).Max) }},
+	}
+}
+
+var configColumns = []column[configSetting]{
+	{"key", func(s configSetting) string { return s.Key }},
+	{"value", func(s configSetting) string { return s.Value }},
+	{"source", func(s configSetting) string { return s.Source }},
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/pjtf93/weathercli"
+)
+
+// LocationFlags narrow down which geocoding match a command uses.
+type LocationFlags struct {
+	Country string `help:"Only match locations in this country (ISO code, e.g. US)." placeholder:"CC"`
+	Admin   string `help:"Only match locations in this state or region (e.g. 'Oregon')." placeholder:"REGION"`
+	Pick    int    `help:"Use the Nth search result instead of the best match." placeholder:"N"`
+}
+
+func (f LocationFlags) searchOptions() weathercli.SearchOptions {
+	return weathercli.SearchOptions{CountryCode: f.Country, Admin: f.Admin}
+}
+
+// resolveLocation turns a location argument into a Location. Saved
+// "@name" references, coordinates and "id:N" skip the search request; names
+// are geocoded and one match is picked: the --pick result, the user's
+// choice when results are ambiguous and the terminal is interactive, or the
+// best match.
+func (a *App) resolveLocation(ctx context.Context, query string, flags LocationFlags) (weathercli.Location, error) {
+	if query == "" {
+		return weathercli.Location{}, usagef("no location given (pass one, or set a default with: weathercli config set location @home)")
+	}
+	if flags.Pick < 0 {
+		return weathercli.Location{}, usagef("--pick must be positive")
+	}
+
+	if name, ok := strings.CutPrefix(query, "@"); ok {
+		return a.savedLocation(name)
+	}
+
+	lat, lon, err := weathercli.ParseCoordinates(query)
+	if err == nil {
+		return weathercli.Location{Name: fmt.Sprintf("%.4f, %.4f", lat, lon), Latitude: lat, Longitude: lon}, nil
+	}
+	if !errors.Is(err, weathercli.ErrNotCoordinates) {
+		return weathercli.Location{}, &weathercli.UsageError{Err: err}
+	}
+
+	if rest, ok := cutPrefixFold(query, "id:"); ok {
+		id, err := strconv.ParseInt(strings.TrimSpace(rest), 10, 64)
+		if err != nil || id <= 0 {
+			return weathercli.Location{}, usagef("invalid location id %q", rest)
+		}
+		loc, err := a.client.LocationByID(ctx, id)
+		if err != nil {
+			return weathercli.Location{}, err
+		}
+		return *loc, nil
+	}
+
+	locations, err := a.client.SearchLocation(ctx, query, flags.searchOptions())
+	if err != nil {
+		return weathercli.Location{}, err
+	}
+
+	switch {
+	case flags.Pick > 0:
+		if flags.Pick > len(locations) {
+			return weathercli.Location{}, usagef("--pick %d out of range: %d matches for %q", flags.Pick, len(locations), query)
+		}
+		return locations[flags.Pick-1], nil
+	case a.interactive && !a.structured() && ambiguous(locations):
+		return a.promptLocation(query, locations)
+	}
+	return locations[0], nil
+}
+
+// cutPrefixFold is strings.CutPrefix ignoring case.
+// resolveTimezone fills in loc's timezone if it has none, as for
+// coordinates; the forecast API resolves it.
+func (a *App) resolveTimezone(ctx context.Context, loc weathercli.Location) (weathercli.Location, error) {
+	if loc.Timezone != "" {
+		return loc, nil
+	}
+	weather, err := a.client.CurrentByCoords(ctx, loc.Latitude, loc.Longitude, &loc)
+	if err != nil {
+		return loc, err
+	}
+	loc.Timezone = weather.Location.Timezone
+	return loc, nil
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
+
+// ambiguous reports whether the top results share a name without the best
+// match clearly dominating by population, as with "Portland" or
+// "Springfield".
+func ambiguous(locations []weathercli.Location) bool {
+	if len(locations) < 2 || !strings.EqualFold(locations[0].Name, locations[1].Name) {
+		return false
+	}
+	first, second := locations[0].Population, locations[1].Population
+	return first == 0 || second == 0 || first < 10*second
+}
+
+// promptLocation asks the user to choose among same-named matches.
+func (a *App) promptLocation(query string, locations []weathercli.Location) (weathercli.Location, error) {
+	var choices []weathercli.Location
+	for _, loc := range locations {
+		if strings.EqualFold(loc.Name, locations[0].Name) {
+			choices = append(choices, loc)
+		}
+	}
+
+	fmt.Fprintf(a.err, "Multiple matches for %q:\n", query)
+	for i, loc := range choices {
+		fmt.Fprintf(a.err, "  %d) %s%s\n", i+1, locationLabel(loc), formatLocationDetail(loc))
+	}
+	fmt.Fprintf(a.err, "Choose [1-%d] (default 1): ", len(choices))
+
+	line, err := bufio.NewReader(a.in).ReadString('\n')
+	if err != nil && line == "" {
+		return weathercli.Location{}, fmt.Errorf("no location chosen")
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return choices[0], nil
+	}
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 || n > len(choices) {
+		return weathercli.Location{}, fmt.Errorf("invalid choice %q", line)
+	}
+	return choices[n-1], nil
+}
+
+// formatLocationDetail returns " (county, pop. N)" for telling same-named
+// places apart, or "" when nothing is known.
+func formatLocationDetail(loc weathercli.Location) string {
+	var parts []string
+	if loc.Admin2 != "" {
+		parts = append(parts, loc.Admin2)
+	}
+	if loc.Population > 0 {
+		parts = append(parts, "pop. "+formatThousands(loc.Population))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// formatThousands formats n with comma separators.
+func formatThousands(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// isTerminal reports whether f is an interactive terminal.
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pjtf93/weathercli"
+)
+
+func TestResolveTimezone(t *testing.T) {
+	app := newMCPApp(mcpBackend(t, nil), weathercli.NewConfig())
+
+	loc, err := app.resolveLocation(context.Background(), "52.52,13.41", LocationFlags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Timezone != "" {
+		t.Fatalf("coordinates resolved with timezone %q, want none", loc.Timezone)
+	}
+	loc, err = app.resolveTimezone(context.Background(), loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Timezone != "UTC" || loc.Name != "52.5200, 13.4100" {
+		t.Errorf("resolveTimezone = %+v, want the API's timezone and the same name", loc)
+	}
+
+	named := weathercli.Location{Name: "Tokyo", Timezone: "Asia/Tokyo"}
+	if got, err := app.resolveTimezone(context.Background(), named); err != nil || got.Timezone != "Asia/Tokyo" {
+		t.Errorf("resolveTimezone(%+v) = %+v, %v, want it unchanged", named, got, err)
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// LocationsCmd manages saved locations.
+type LocationsCmd struct {
+	Add    LocationsAddCmd    `cmd:"" help:"Save a location under a name."`
+	List   LocationsListCmd   `cmd:"" default:"1" help:"List saved locations."`
+	Remove LocationsRemoveCmd `cmd:"" aliases:"rm" help:"Remove a saved location."`
+	Rename LocationsRenameCmd `cmd:"" help:"Rename a saved location."`
+}
+
+// LocationsAddCmd saves a location.
+type LocationsAddCmd struct {
+	Name          string `arg:"" name:"name" help:"Name to save the location under (e.g. 'home', 'office-berlin')."`
+	Location      string `arg:"" name:"location" help:"Location name, coordinates or id:N to resolve."`
+	LocationFlags `embed:""`
+	Force         bool `help:"Replace an existing location with the same name."`
+}
+
+// LocationsListCmd lists saved locations.
+type LocationsListCmd struct {
+	Names bool `help:"Print only the names, one per line."`
+}
+
+// LocationsRemoveCmd removes a saved location.
+type LocationsRemoveCmd struct {
+	Name string `arg:"" name:"name" help:"Saved location name."`
+}
+
+// LocationsRenameCmd renames a saved location.
+type LocationsRenameCmd struct {
+	Name    string `arg:"" name:"name" help:"Saved location name."`
+	NewName string `arg:"" name:"new-name" help:"New name."`
+}
+
+// savedLocations loads the saved locations file.
+func (a *App) savedLocations() (weathercli.SavedLocations, error) {
+	if a.savedPath == "" {
+		return nil, fmt.Errorf("no config directory for saved locations")
+	}
+	return weathercli.LoadSavedLocations(a.savedPath)
+}
+
+// savedLocation resolves an "@name" reference.
+func (a *App) savedLocation(name string) (weathercli.Location, error) {
+	saved, err := a.savedLocations()
+	if err != nil {
+		return weathercli.Location{}, err
+	}
+	loc, ok := saved[name]
+	if !ok {
+		return weathercli.Location{}, fmt.Errorf("%w: no saved location %q (see 'weathercli locations list')", weathercli.ErrLocationNotFound, name)
+	}
+	return loc, nil
+}
+
+// Run for LocationsAddCmd.
+func (c *LocationsAddCmd) Run(app *App, ctx context.Context) error {
+	name := strings.TrimPrefix(c.Name, "@")
+	if err := weathercli.ValidateSavedName(name); err != nil {
+		return &weathercli.UsageError{Err: err}
+	}
+	saved, err := app.savedLocations()
+	if err != nil {
+		return err
+	}
+	if _, exists := saved[name]; exists && !c.Force {
+		return usagef("location %q already saved (use --force to replace it)", name)
+	}
+
+	loc, err := app.resolveLocation(ctx, c.Location, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+	if _, _, err := weathercli.ParseCoordinates(c.Location); err == nil {
+		// Bare coordinates have no place name; label them with the saved name.
+		loc.Name = name
+	}
+	if loc, err = app.resolveTimezone(ctx, loc); err != nil {
+		return err
+	}
+
+	saved[name] = loc
+	if err := saved.Save(app.savedPath); err != nil {
+		return err
+	}
+
+	if app.structured() {
+		return app.renderData(map[string]weathercli.Location{name: loc})
+	}
+	fmt.Fprintf(app.out, "Saved %s: %s (%.4f, %.4f)\n", app.color.Bold("@"+name), locationLabel(loc), loc.Latitude, loc.Longitude)
+	return nil
+}
+
+// Run for LocationsListCmd.
+func (c *LocationsListCmd) Run(app *App) error {
+	saved, err := app.savedLocations()
+	if err != nil {
+		return err
+	}
+
+	if c.Names {
+		for _, name := range saved.Names() {
+			fmt.Fprintln(app.out, name)
+		}
+		return nil
+	}
+	if app.structured() {
+		return app.renderData(saved)
+	}
+
+	if len(saved) == 0 {
+		fmt.Fprintln(app.out, "No saved locations. Add one with: weathercli locations add home \"Berlin\"")
+		return nil
+	}
+	width := 0
+	for _, name := range saved.Names() {
+		width = max(width, len(name)+1)
+	}
+	for _, name := range saved.Names() {
+		loc := saved[name]
+		fmt.Fprintf(app.out, "%s  %s  %s\n",
+			app.color.Bold(fmt.Sprintf("%-*s", width, "@"+name)),
+			locationLabel(loc),
+			app.color.Cyan(fmt.Sprintf("(%.4f, %.4f %s)", loc.Latitude, loc.Longitude, loc.Timezone)))
+	}
+	return nil
+}
+
+// Run for LocationsRemoveCmd.
+func (c *LocationsRemoveCmd) Run(app *App) error {
+	name := strings.TrimPrefix(c.Name, "@")
+	saved, err := app.savedLocations()
+	if err != nil {
+		return err
+	}
+	if _, ok := saved[name]; !ok {
+		return fmt.Errorf("%w: no saved location %q", weathercli.ErrLocationNotFound, name)
+	}
+
+	delete(saved, name)
+	if err := saved.Save(app.savedPath); err != nil {
+		return err
+	}
+	if !app.structured() {
+		fmt.Fprintf(app.out, "Removed @%s\n", name)
+	}
+	return nil
+}
+
+// Run for LocationsRenameCmd.
+func (c *LocationsRenameCmd) Run(app *App) error {
+	name, newName := strings.TrimPrefix(c.Name, "@"), strings.TrimPrefix(c.NewName, "@")
+	if err := weathercli.ValidateSavedName(newName); err != nil {
+		return &weathercli.UsageError{Err: err}
+	}
+	saved, err := app.savedLocations()
+	if err != nil {
+		return err
+	}
+	loc, ok := saved[name]
+	if !ok {
+		return fmt.Errorf("%w: no saved location %q", weathercli.ErrLocationNotFound, name)
+	}
+	if _, exists := saved[newName]; exists {
+		return usagef("location %q already saved", newName)
+	}
+
+	delete(saved, name)
+	saved[newName] = loc
+	if err := saved.Save(app.savedPath); err != nil {
+		return err
+	}
+	if !app.structured() {
+		fmt.Fprintf(app.out, "Renamed @%s to @%s\n", name, newName)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/pjtf93/weathercli"
+)
+
+func TestLocationsUsageErrors(t *testing.T) {
+	app := newMCPApp(mcpBackend(t, nil), weathercli.NewConfig())
+	app.out = io.Discard
+	app.savedPath = filepath.Join(t.TempDir(), "locations.json")
+	ctx := context.Background()
+
+	for _, name := range []string{"home", "work"} {
+		if err := (&LocationsAddCmd{Name: name, Location: "Berlin"}).Run(app, ctx); err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+		want int
+	}{
+		{"add invalid name", func() error { return (&LocationsAddCmd{Name: "my home", Location: "Berlin"}).Run(app, ctx) }, exitCodeUsage},
+		{"add existing", func() error { return (&LocationsAddCmd{Name: "home", Location: "Berlin"}).Run(app, ctx) }, exitCodeUsage},
+		{"rename to invalid name", func() error { return (&LocationsRenameCmd{Name: "home", NewName: "my home"}).Run(app) }, exitCodeUsage},
+		{"rename to existing", func() error { return (&LocationsRenameCmd{Name: "home", NewName: "@work"}).Run(app) }, exitCodeUsage},
+		{"rename missing", func() error { return (&LocationsRenameCmd{Name: "gone", NewName: "new"}).Run(app) }, exitCodeNotFound},
+		{"remove missing", func() error { return (&LocationsRemoveCmd{Name: "gone"}).Run(app) }, exitCodeNotFound},
+	}
+	for _, tt := range tests {
+		err := tt.run()
+		if err == nil {
+			t.Errorf("%s: no error", tt.name)
+			continue
+		}
+		if got := exitCode(err); got != tt.want {
+			t.Errorf("%s: exit code %d (%v), want %d", tt.name, got, err, tt.want)
+		}
+	}
+
+	if err := (&LocationsAddCmd{Name: "home", Location: "Berlin", Force: true}).Run(app, ctx); err != nil {
+		t.Errorf("add --force over an existing name: %v", err)
+	}
+}
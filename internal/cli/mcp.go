@@ -0,0 +1,475 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// MCPCmd serves the Model Context Protocol over stdio.
+type MCPCmd struct{}
+
+// mcpProtocolVersions are the protocol revisions the server speaks, newest
+// first.
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// mcpTool is a command exposed as an MCP tool. Its input schema is derived
+// from the command's flags and arguments, minus skip.
+type mcpTool struct {
+	name        string
+	command     string // config section, e.g. "forecast"
+	description string
+	new         func() mcpRunner
+	skip        []string // flag names not exposed
+}
+
+type mcpRunner interface {
+	Run(app *App, ctx context.Context) error
+}
+
+var mcpTools = []mcpTool{
+	{
+		name:        "current_weather",
+		command:     "current",
+		description: "Get current weather conditions (temperature, feels-like, humidity, wind, precipitation, pressure, UV) for a location.",
+		new:         func() mcpRunner { return &CurrentCmd{} },
+		skip:        []string{"from-file", "watch"},
+	},
+	{
+		name:        "forecast",
+		command:     "forecast",
+		description: "Get a daily forecast (up to 16 days) or, with hourly, an hourly forecast for a location. With ensemble, returns the spread of ensemble members (mean, p10/p90, min/max) and the chance of precipitation instead.",
+		new:         func() mcpRunner { return &ForecastCmd{} },
+		skip:        []string{"from-file", "watch"},
+	},
+	{
+		name:        "search_location",
+		command:     "search",
+		description: "Search for locations by name; returns coordinates, country, region, timezone and population for each match.",
+		new:         func() mcpRunner { return &SearchCmd{} },
+	},
+}
+
+// jsonrpcRequest is a JSON-RPC 2.0 request or notification (no ID).
+type jsonrpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonrpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+type jsonrpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC error codes.
+const (
+	jsonrpcParseError     = -32700
+	jsonrpcInvalidRequest = -32600
+	jsonrpcMethodNotFound = -32601
+	jsonrpcInvalidParams  = -32602
+)
+
+// Run for MCPCmd. It reads newline-delimited JSON-RPC messages from stdin
+// and writes responses to stdout until stdin is closed.
+func (c *MCPCmd) Run(app *App, ctx context.Context) error {
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(app.in)
+		scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- bytes.Clone(scanner.Bytes()):
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	var mu sync.Mutex // one response line at a time
+	enc := json.NewEncoder(app.out)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-scanErr:
+					return err
+				default: // interrupted
+					return nil
+				}
+			}
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			// Tool calls run concurrently so a slow request does not hold
+			// up pings or other calls.
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := app.handleMCP(ctx, line); resp != nil {
+					mu.Lock()
+					defer mu.Unlock()
+					_ = enc.Encode(resp)
+				}
+			}()
+		}
+	}
+}
+
+// handleMCP handles one message, returning nil for notifications.
+func (a *App) handleMCP(ctx context.Context, line []byte) *jsonrpcResponse {
+	var req jsonrpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &jsonrpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &jsonrpcError{jsonrpcParseError, err.Error()}}
+	}
+	if req.ID == nil {
+		return nil // notifications/initialized, notifications/cancelled, ...
+	}
+	resp := &jsonrpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &jsonrpcError{jsonrpcInvalidRequest, "invalid JSON-RPC 2.0 request"}
+		return resp
+	}
+	if a.verbose {
+		a.renderVerbose("MCP %s", req.Method)
+	}
+
+	var err *jsonrpcError
+	switch req.Method {
+	case "initialize":
+		resp.Result, err = a.mcpInitialize(req.Params)
+	case "ping":
+		resp.Result = struct{}{}
+	case "tools/list":
+		resp.Result, err = a.mcpListTools()
+	case "tools/call":
+		resp.Result, err = a.mcpCallTool(ctx, req.Params)
+	default:
+		err = &jsonrpcError{jsonrpcMethodNotFound, "method not found: " + req.Method}
+	}
+	resp.Error = err
+	return resp
+}
+
+func (a *App) mcpInitialize(params json.RawMessage) (any, *jsonrpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &jsonrpcError{jsonrpcInvalidParams, err.Error()}
+	}
+	version := mcpProtocolVersions[0]
+	if slices.Contains(mcpProtocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]string{"name": "weathercli", "version": Version},
+		"instructions":    "Weather from Open-Meteo. Locations can be names ('Denver, Colorado'), coordinates ('52.52,13.41'), GeoNames ids ('id:2950159') or saved names ('@home').",
+	}, nil
+}
+
+func (a *App) mcpListTools() (any, *jsonrpcError) {
+	var tools []map[string]any
+	for _, t := range mcpTools {
+		tools = append(tools, map[string]any{
+			"name":        t.name,
+			"description": t.description,
+			"inputSchema": a.toolSchema(t),
+		})
+	}
+	return map[string]any{"tools": tools}, nil
+}
+
+// mcpCallTool runs a tool's command with JSON output captured as the result.
+// Tool failures are results with isError set, so the agent sees them.
+func (a *App) mcpCallTool(ctx context.Context, params json.RawMessage) (any, *jsonrpcError) {
+	var p struct {
+		Name      string                     `json:"name"`
+		Arguments map[string]json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &jsonrpcError{jsonrpcInvalidParams, err.Error()}
+	}
+	i := slices.IndexFunc(mcpTools, func(t mcpTool) bool { return t.name == p.Name })
+	if i < 0 {
+		return nil, &jsonrpcError{jsonrpcInvalidParams, "unknown tool: " + p.Name}
+	}
+	tool := mcpTools[i]
+
+	cmd := tool.new()
+	if err := a.setToolArguments(tool, cmd, p.Arguments); err != nil {
+		return nil, &jsonrpcError{jsonrpcInvalidParams, err.Error()}
+	}
+
+	var out bytes.Buffer
+	run := *a
+	run.out, run.err = &out, io.Discard
+	run.format, run.emitted = formatJSON, false
+	run.interactive, run.tty, run.verbose = false, false, false
+	if err := cmd.Run(&run, ctx); err != nil {
+		return map[string]any{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}, nil
+	}
+
+	text := strings.TrimSpace(out.String())
+	result := map[string]any{"content": []map[string]string{{"type": "text", "text": text}}}
+	var structured any
+	if err := json.Unmarshal(out.Bytes(), &structured); err == nil {
+		if _, ok := structured.(map[string]any); !ok {
+			structured = map[string]any{"results": structured} // must be an object
+		}
+		result["structuredContent"] = structured
+	}
+	return result, nil
+}
+
+// toolField is a flag or argument of a command struct, as an MCP tool
+// parameter.
+type toolField struct {
+	name     string // kong name, e.g. "from-file"
+	field    reflect.Value
+	tag      reflect.StructTag
+	arg      bool
+	required bool
+}
+
+// toolFields lists the flags and arguments of cmd (a pointer to a command
+// struct), including embedded flag groups.
+func toolFields(cmd any, skip []string) []toolField {
+	var fields []toolField
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if _, ok := sf.Tag.Lookup("embed"); ok {
+				walk(v.Field(i))
+				continue
+			}
+			name := sf.Tag.Get("name")
+			if name == "" {
+				name = kebabCase(sf.Name)
+			}
+			if slices.Contains(skip, name) {
+				continue
+			}
+			_, arg := sf.Tag.Lookup("arg")
+			_, optional := sf.Tag.Lookup("optional")
+			_, required := sf.Tag.Lookup("required")
+			fields = append(fields, toolField{
+				name:     name,
+				field:    v.Field(i),
+				tag:      sf.Tag,
+				arg:      arg,
+				required: required || (arg && !optional),
+			})
+		}
+	}
+	walk(reflect.ValueOf(cmd).Elem())
+	return fields
+}
+
+// toolSchema derives a JSON schema for a tool's arguments from its
+// command's kong tags: help, enum, defaults (including config file
+// defaults) and required flags.
+func (a *App) toolSchema(t mcpTool) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for _, f := range toolFields(t.new(), t.skip) {
+		prop := map[string]any{"description": f.tag.Get("help")}
+		switch f.field.Interface().(type) {
+		case int:
+			prop["type"] = "integer"
+		case float64:
+			prop["type"] = "number"
+		case bool:
+			prop["type"] = "boolean"
+		default: // string, []string (one location), time.Duration
+			prop["type"] = "string"
+		}
+		if enum := f.tag.Get("enum"); enum != "" {
+			prop["enum"] = strings.Split(enum, ",")
+		}
+		def, hasDefault := a.toolDefault(t, f)
+		if hasDefault && def != "" {
+			if v, err := parseToolValue(f.field.Type(), def); err == nil {
+				if list, ok := v.([]string); ok {
+					v = list[0] // a location, given as a string
+				}
+				prop["default"] = v
+			}
+		}
+		if f.required || (f.arg && def == "") {
+			required = append(required, snakeCase(f.name))
+		}
+		properties[snakeCase(f.name)] = prop
+	}
+	schema := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// toolDefault returns a field's default: the config file's value for the
+// command or flag, else the kong default with ${default_location} expanded.
+func (a *App) toolDefault(t mcpTool, f toolField) (string, bool) {
+	if a.config != nil && !f.arg {
+		for _, key := range []string{t.command + "." + f.name, f.name} {
+			if v, ok := a.config.Get(key); ok {
+				return v, true
+			}
+		}
+	}
+	def, ok := f.tag.Lookup("default")
+	if strings.Contains(def, "${default_location}") {
+		location := ""
+		if a.config != nil {
+			location, _ = a.config.Get(configLocationKey)
+		}
+		def = strings.ReplaceAll(def, "${default_location}", location)
+	}
+	return def, ok
+}
+
+// setToolArguments fills cmd from defaults and the call's arguments.
+func (a *App) setToolArguments(t mcpTool, cmd any, args map[string]json.RawMessage) error {
+	known := map[string]bool{}
+	for _, f := range toolFields(cmd, t.skip) {
+		key := snakeCase(f.name)
+		known[key] = true
+		raw, given := args[key]
+		if !given {
+			def, ok := a.toolDefault(t, f)
+			if !ok {
+				continue
+			}
+			v, err := parseToolValue(f.field.Type(), def)
+			if err != nil {
+				return fmt.Errorf("%s: invalid default %q", key, def)
+			}
+			f.field.Set(reflect.ValueOf(v))
+			continue
+		}
+		if err := setToolValue(f.field, raw); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if enum := f.tag.Get("enum"); enum != "" && !slices.Contains(strings.Split(enum, ","), fmt.Sprint(f.field.Interface())) {
+			return fmt.Errorf("%s must be one of %s", key, strings.ReplaceAll(enum, ",", ", "))
+		}
+	}
+	for key := range args {
+		if !known[key] {
+			return fmt.Errorf("unknown argument %q", key)
+		}
+	}
+	return nil
+}
+
+// parseToolValue parses a default or config value for a field of type t.
+func parseToolValue(t reflect.Type, s string) (any, error) {
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return time.ParseDuration(s)
+	case reflect.TypeOf([]string(nil)):
+		return []string{s}, nil
+	}
+	switch t.Kind() {
+	case reflect.Int:
+		return strconv.Atoi(s)
+	case reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.String:
+		return s, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// setToolValue decodes a JSON argument into field. A string sets a
+// []string field (a location argument) to one element.
+func setToolValue(field reflect.Value, raw json.RawMessage) error {
+	switch field.Interface().(type) {
+	case []string:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("must be a string")
+		}
+		field.Set(reflect.ValueOf([]string{s}))
+		return nil
+	case time.Duration:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("must be a duration string such as \"5m\"")
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(d))
+		return nil
+	}
+	if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+		kind := "a string"
+		switch field.Kind() {
+		case reflect.Int:
+			kind = "an integer"
+		case reflect.Float64:
+			kind = "a number"
+		case reflect.Bool:
+			kind = "a boolean"
+		}
+		return fmt.Errorf("must be %s", kind)
+	}
+	return nil
+}
+
+// kebabCase turns a Go field name into kong's flag name: FromFile becomes
+// from-file.
+func kebabCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func snakeCase(name string) string { return strings.ReplaceAll(name, "-", "_") }
@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// mcpBackend serves geocoding for Berlin and current weather that echoes
+// the requested latitude as the temperature.
+func mcpBackend(t *testing.T, onForecast func()) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			if r.URL.Query().Get("name") != "Berlin" {
+				_, _ = w.Write([]byte(`{}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"name":"Berlin","latitude":52.52,"longitude":13.41,"country":"Germany","timezone":"Europe/Berlin"}]}`))
+		case "/forecast":
+			if onForecast != nil {
+				onForecast()
+			}
+			lat := r.URL.Query().Get("latitude")
+			_, _ = fmt.Fprintf(w, `{"latitude":%s,"longitude":13.41,"timezone":"UTC","current":{"time":"2024-01-12T09:00","temperature_2m":%s}}`, lat, lat)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newMCPApp(srv *httptest.Server, config *weathercli.Config) *App {
+	return &App{
+		client: weathercli.NewClient(weathercli.Options{BaseURL: srv.URL, GeoBaseURL: srv.URL}),
+		err:    io.Discard,
+		format: formatHuman,
+		color:  NewColor(false),
+		config: config,
+	}
+}
+
+type mcpResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonrpcError   `json:"error"`
+}
+
+// serveMCP runs the server over the given request lines until they are
+// consumed, returning the responses by ID.
+func serveMCP(t *testing.T, app *App, requests ...string) map[string]mcpResponse {
+	t.Helper()
+	var out bytes.Buffer
+	app.in = strings.NewReader(strings.Join(requests, "\n") + "\n")
+	app.out = &out
+	if err := (&MCPCmd{}).Run(app, context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	responses := map[string]mcpResponse{}
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp mcpResponse
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("decoding response: %v\n%s", err, out.String())
+		}
+		responses[string(resp.ID)] = resp
+	}
+	return responses
+}
+
+func TestMCPServer(t *testing.T) {
+	app := newMCPApp(mcpBackend(t, nil), weathercli.NewConfig())
+
+	tests := []struct {
+		name    string
+		request string
+		errCode int    // JSON-RPC error code, 0 for a result
+		result  string // JSON object the result must contain
+	}{
+		{
+			name:    "initialize",
+			request: `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{}}}`,
+			result:  `{"protocolVersion":"2024-11-05","capabilities":{"tools":{}},"serverInfo":{"name":"weathercli"}}`,
+		},
+		{
+			name:    "initialize with unknown version",
+			request: `{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
+			result:  `{"protocolVersion":"2025-06-18"}`,
+		},
+		{
+			name:    "ping",
+			request: `{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+			result:  `{}`,
+		},
+		{
+			name:    "tools/list",
+			request: `{"jsonrpc":"2.0","id":4,"method":"tools/list"}`,
+			result:  `{"tools":[{"name":"current_weather"},{"name":"forecast"},{"name":"search_location"}]}`,
+		},
+		{
+			name:    "call current_weather",
+			request: `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"current_weather","arguments":{"location":"Berlin"}}}`,
+			result:  `{"structuredContent":{"temperature":52.52,"location":{"name":"Berlin","country":"Germany"},"schema_version":"1"}}`,
+		},
+		{
+			name:    "call search_location",
+			request: `{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"search_location","arguments":{"query":"Berlin","limit":1}}}`,
+			result:  `{"structuredContent":{"results":[{"name":"Berlin","latitude":52.52}]}}`,
+		},
+		{
+			name:    "tool failure",
+			request: `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"current_weather","arguments":{"location":"Nowhere"}}}`,
+			result:  `{"isError":true,"content":[{"type":"text","text":"location not found: Nowhere"}]}`,
+		},
+		{
+			name:    "unknown tool",
+			request: `{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"radar","arguments":{}}}`,
+			errCode: jsonrpcInvalidParams,
+		},
+		{
+			name:    "bad argument",
+			request: `{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"forecast","arguments":{"location":"Berlin","days":"many"}}}`,
+			errCode: jsonrpcInvalidParams,
+		},
+		{
+			name:    "unknown method",
+			request: `{"jsonrpc":"2.0","id":10,"method":"resources/list"}`,
+			errCode: jsonrpcMethodNotFound,
+		},
+		{
+			name:    "wrong version",
+			request: `{"jsonrpc":"1.0","id":11,"method":"ping"}`,
+			errCode: jsonrpcInvalidRequest,
+		},
+		{
+			name:    "parse error",
+			request: `{"jsonrpc":`,
+			errCode: jsonrpcParseError,
+		},
+	}
+
+	var requests []string
+	for _, tt := range tests {
+		requests = append(requests, tt.request)
+	}
+	// Notifications get no response.
+	requests = append(requests, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	responses := serveMCP(t, app, requests...)
+	if len(responses) != len(tests) {
+		t.Errorf("got %d responses, want %d", len(responses), len(tests))
+	}
+
+	for i, tt := range tests {
+		id := fmt.Sprint(i + 1)
+		if tt.errCode == jsonrpcParseError {
+			id = "null"
+		}
+		resp, ok := responses[id]
+		if !ok {
+			t.Errorf("%s: no response with id %s", tt.name, id)
+			continue
+		}
+		if tt.errCode != 0 {
+			if resp.Error == nil || resp.Error.Code != tt.errCode {
+				t.Errorf("%s: error = %+v, want code %d", tt.name, resp.Error, tt.errCode)
+			}
+			continue
+		}
+		if resp.Error != nil {
+			t.Errorf("%s: error = %+v", tt.name, resp.Error)
+			continue
+		}
+		var got, want any
+		if err := json.Unmarshal(resp.Result, &got); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if err := json.Unmarshal([]byte(tt.result), &want); err != nil {
+			t.Fatalf("%s: bad want: %v", tt.name, err)
+		}
+		if !jsonContains(got, want) {
+			t.Errorf("%s: result = %s, want it to contain %s", tt.name, resp.Result, tt.result)
+		}
+	}
+}
+
+// jsonContains reports whether got has every field of want, recursively;
+// arrays must have the same length.
+func jsonContains(got, want any) bool {
+	switch want := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range want {
+			if !jsonContains(g[k], v) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !jsonContains(g[i], want[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(got, want)
+}
+
+func TestMCPToolSchema(t *testing.T) {
+	forecast := mcpTools[1]
+
+	tests := []struct {
+		name     string
+		config   map[string]string
+		prop     string
+		want     map[string]any // expected keys of the property, besides description
+		required []string
+	}{
+		{"argument", nil, "location", map[string]any{"type": "string"}, []string{"location"}},
+		{"int default", nil, "days", map[string]any{"type": "integer", "default": 7}, []string{"location"}},
+		{"bool", nil, "hourly", map[string]any{"type": "boolean"}, []string{"location"}},
+		{"float", nil, "precip_threshold", map[string]any{"type": "number"}, []string{"location"}},
+		{"embedded flag", nil, "country", map[string]any{"type": "string"}, []string{"location"}},
+		{"config default", map[string]string{"forecast.days": "3"}, "days", map[string]any{"type": "integer", "default": 3}, []string{"location"}},
+		{"default location", map[string]string{"location": "Berlin"}, "location", map[string]any{"type": "string", "default": "Berlin"}, nil},
+	}
+
+	for _, tt := range tests {
+		config := weathercli.NewConfig()
+		for k, v := range tt.config {
+			config.Set(k, v)
+		}
+		app := &App{config: config}
+		schema := app.toolSchema(forecast)
+
+		if schema["type"] != "object" || schema["additionalProperties"] != false {
+			t.Errorf("%s: schema = %v, want a closed object", tt.name, schema)
+		}
+		properties := schema["properties"].(map[string]any)
+		for _, skipped := range forecast.skip {
+			if _, ok := properties[snakeCase(skipped)]; ok {
+				t.Errorf("%s: skipped flag %s is in the schema", tt.name, skipped)
+			}
+		}
+		prop, ok := properties[tt.prop].(map[string]any)
+		if !ok {
+			t.Errorf("%s: no property %s in %v", tt.name, tt.prop, properties)
+			continue
+		}
+		if prop["description"] == "" {
+			t.Errorf("%s: %s has no description", tt.name, tt.prop)
+		}
+		delete(prop, "description")
+		if !reflect.DeepEqual(prop, tt.want) {
+			t.Errorf("%s: %s = %v, want %v", tt.name, tt.prop, prop, tt.want)
+		}
+		required, _ := schema["required"].([]string)
+		if !reflect.DeepEqual(required, tt.required) {
+			t.Errorf("%s: required = %v, want %v", tt.name, required, tt.required)
+		}
+	}
+}
+
+func TestMCPSetToolArguments(t *testing.T) {
+	forecast := mcpTools[1]
+	config := weathercli.NewConfig()
+	config.Set("forecast.hours", "12")
+	app := &App{config: config}
+
+	tests := []struct {
+		args    string
+		want    ForecastCmd
+		wantErr string
+	}{
+		{
+			args: `{"location":"Paris"}`,
+			want: ForecastCmd{Locations: []string{"Paris"}, Days: 7, Hours: 12},
+		},
+		{
+			args: `{"location":"Paris","days":3,"hourly":true,"hours":48,"pick":2,"precip_threshold":0.5}`,
+			want: ForecastCmd{Locations: []string{"Paris"}, LocationFlags: LocationFlags{Pick: 2}, Days: 3, Hourly: true, Hours: 48, PrecipThreshold: 0.5},
+		},
+		{args: `{"location":"Paris","days":"3"}`, wantErr: "days: must be an integer"},
+		{args: `{"location":"Paris","hourly":1}`, wantErr: "hourly: must be a boolean"},
+		{args: `{"location":["Paris"]}`, wantErr: "location: must be a string"},
+		{args: `{"location":"Paris","watch":"5m"}`, wantErr: `unknown argument "watch"`},
+	}
+
+	for _, tt := range tests {
+		var args map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		var cmd ForecastCmd
+		err := app.setToolArguments(forecast, &cmd, args)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error = %v, want %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(cmd, tt.want) {
+			t.Errorf("%s: cmd = %+v, want %+v", tt.args, cmd, tt.want)
+		}
+	}
+}
+
+func TestMCPConcurrentCalls(t *testing.T) {
+	const calls = 4
+	var arrived atomic.Int32
+	release := make(chan struct{})
+	var once sync.Once
+	// Each upstream request waits until all calls are in flight, so the
+	// test only finishes if the server runs them concurrently.
+	srv := mcpBackend(t, func() {
+		if arrived.Add(1) == calls {
+			once.Do(func() { close(release) })
+		}
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+			t.Error("tool calls did not run concurrently")
+		}
+	})
+	app := newMCPApp(srv, weathercli.NewConfig())
+
+	var requests []string
+	for i := 1; i <= calls; i++ {
+		requests = append(requests, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"current_weather","arguments":{"location":"%d,13.41"}}}`, i, 10*i))
+	}
+	responses := serveMCP(t, app, requests...)
+
+	for i := 1; i <= calls; i++ {
+		resp, ok := responses[fmt.Sprint(i)]
+		if !ok {
+			t.Errorf("no response for call %d", i)
+			continue
+		}
+		var result struct {
+			StructuredContent weathercli.CurrentWeather `json:"structuredContent"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			t.Fatal(err)
+		}
+		if got := result.StructuredContent.Temperature; got != float64(10*i) {
+			t.Errorf("call %d: temperature = %v, want %d", i, got, 10*i)
+		}
+	}
+}
@@ -1,47 +1,42 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pjtf93/weathercli"
 )
 
 // RenderCurrentWeather outputs current weather in human or JSON format.
 func (a *App) RenderCurrentWeather(w *weathercli.CurrentWeather) error {
-	if a.json {
-		return json.NewEncoder(a.out).Encode(w)
+	if a.structured() {
+		return a.renderData(w)
 	}
 
-	loc := w.Location
-	locStr := loc.Name
-	if loc.Admin1 != "" {
-		locStr += ", " + loc.Admin1
-	}
-	if loc.Country != "" {
-		locStr += ", " + loc.Country
-	}
+	locStr := locationLabel(w.Location)
 
 	fmt.Fprintf(a.out, "%s\n", a.color.Bold(locStr))
-	fmt.Fprintf(a.out, "%s\n\n", a.color.Cyan(w.Time.Format("Mon Jan 2, 2006 15:04 MST")))
+	fmt.Fprintf(a.out, "%s\n", a.color.Cyan(w.Time.Format("Mon Jan 2, 2006 15:04 MST")))
+	a.renderStale(w.Stale, w.CachedAt)
+	fmt.Fprintln(a.out)
 
 	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Condition:"), w.Condition)
-	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Temperature:"), formatTemp(w.Temperature, a.color))
-	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Feels like:"), formatTemp(w.Apparent, a.color))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Temperature:"), formatTemp(w.Temperature, w.Units, a.color))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Feels like:"), formatTemp(w.Apparent, w.Units, a.color))
 	fmt.Fprintf(a.out, "%s %d%%\n", a.color.Bold("Humidity:"), w.Humidity)
-	fmt.Fprintf(a.out, "%s %.1f km/h %s\n", a.color.Bold("Wind:"), w.WindSpeed, weathercli.WindDirection(w.WindDirection))
+	fmt.Fprintf(a.out, "%s %.1f %s %s\n", a.color.Bold("Wind:"), w.WindSpeed, w.Units.WindSpeedSymbol(), weathercli.WindDirection(w.WindDirection))
 	fmt.Fprintf(a.out, "%s %.0f hPa\n", a.color.Bold("Pressure:"), w.Pressure)
 	fmt.Fprintf(a.out, "%s %d%%\n", a.color.Bold("Cloud cover:"), w.CloudCover)
 
 	if w.Precipitation > 0 {
-		fmt.Fprintf(a.out, "%s %.1f mm\n", a.color.Bold("Precipitation:"), w.Precipitation)
+		fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Precipitation:"), formatPrecip(w.Precipitation, w.Units))
 	}
 	if w.Rain > 0 {
-		fmt.Fprintf(a.out, "%s %.1f mm\n", a.color.Bold("Rain:"), w.Rain)
+		fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Rain:"), formatPrecip(w.Rain, w.Units))
 	}
 	if w.Snowfall > 0 {
-		fmt.Fprintf(a.out, "%s %.1f cm\n", a.color.Bold("Snowfall:"), w.Snowfall)
+		fmt.Fprintf(a.out, "%s %.1f %s\n", a.color.Bold("Snowfall:"), w.Snowfall, w.Units.SnowfallSymbol())
 	}
 	if w.UVIndex > 0 {
 		fmt.Fprintf(a.out, "%s %.1f %s\n", a.color.Bold("UV Index:"), w.UVIndex, formatUVLevel(w.UVIndex))
@@ -52,55 +47,56 @@ func (a *App) RenderCurrentWeather(w *weathercli.CurrentWeather) error {
 
 // RenderForecast outputs forecast in human or JSON format.
 func (a *App) RenderForecast(f *weathercli.Forecast) error {
-	if a.json {
-		return json.NewEncoder(a.out).Encode(f)
+	if a.structured() {
+		return a.renderData(f)
 	}
 
-	loc := f.Location
-	locStr := loc.Name
-	if loc.Admin1 != "" {
-		locStr += ", " + loc.Admin1
-	}
-	if loc.Country != "" {
-		locStr += ", " + loc.Country
-	}
+	locStr := locationLabel(f.Location)
+
+	fmt.Fprintf(a.out, "%s\n", a.color.Bold(locStr))
+	a.renderStale(f.Stale, f.CachedAt)
+	fmt.Fprintln(a.out)
 
-	fmt.Fprintf(a.out, "%s\n\n", a.color.Bold(locStr))
+	if a.table {
+		a.renderForecastTable(f)
+		return nil
+	}
 
 	if len(f.Daily) > 0 {
-		a.renderDailyForecast(f.Daily)
+		a.renderDailyForecast(f.Daily, f.Units)
 	}
 
 	if len(f.Hourly) > 0 {
-		a.renderHourlyForecast(f.Hourly)
+		a.renderHourlyForecast(f.Hourly, f.Units)
 	}
 
 	return nil
 }
 
-func (a *App) renderDailyForecast(days []weathercli.DailyForecast) {
+func (a *App) renderDailyForecast(days []weathercli.DailyForecast, units weathercli.Units) {
 	for _, day := range days {
 		date := day.Date.Format("Mon Jan 2")
 		fmt.Fprintf(a.out, "%s\n", a.color.Bold(date))
 		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Condition:"), day.Condition)
 		fmt.Fprintf(a.out, "  %s %s (high) / %s (low)\n",
 			a.color.Cyan("Temperature:"),
-			formatTemp(day.TempMax, a.color),
-			formatTemp(day.TempMin, a.color))
+			formatTemp(day.TempMax, units, a.color),
+			formatTemp(day.TempMin, units, a.color))
 
 		if day.PrecipProb > 0 {
 			fmt.Fprintf(a.out, "  %s %d%%\n", a.color.Cyan("Precipitation:"), day.PrecipProb)
 		}
 		if day.Rain > 0 {
-			fmt.Fprintf(a.out, "  %s %.1f mm\n", a.color.Cyan("Rain:"), day.Rain)
+			fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Rain:"), formatPrecip(day.Rain, units))
 		}
 		if day.Snowfall > 0 {
-			fmt.Fprintf(a.out, "  %s %.1f cm\n", a.color.Cyan("Snowfall:"), day.Snowfall)
+			fmt.Fprintf(a.out, "  %s %.1f %s\n", a.color.Cyan("Snowfall:"), day.Snowfall, units.SnowfallSymbol())
 		}
 
-		fmt.Fprintf(a.out, "  %s %.1f km/h %s\n",
+		fmt.Fprintf(a.out, "  %s %.1f %s %s\n",
 			a.color.Cyan("Wind:"),
 			day.WindSpeedMax,
+			units.WindSpeedSymbol(),
 			weathercli.WindDirection(day.WindDirection))
 
 		sunrise := day.Sunrise.Format("15:04")
@@ -117,37 +113,159 @@ func (a *App) renderDailyForecast(days []weathercli.DailyForecast) {
 	}
 }
 
-func (a *App) renderHourlyForecast(hours []weathercli.HourlyForecast) {
+func (a *App) renderHourlyForecast(hours []weathercli.HourlyForecast, units weathercli.Units) {
+	a.renderSparkline(hours, units)
 	for _, hour := range hours {
 		timeStr := hour.Time.Format("Mon Jan 2 15:04")
 		fmt.Fprintf(a.out, "%s\n", a.color.Bold(timeStr))
 		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Condition:"), hour.Condition)
 		fmt.Fprintf(a.out, "  %s %s (feels %s)\n",
 			a.color.Cyan("Temperature:"),
-			formatTemp(hour.Temperature, a.color),
-			formatTemp(hour.Apparent, a.color))
+			formatTemp(hour.Temperature, units, a.color),
+			formatTemp(hour.Apparent, units, a.color))
 		fmt.Fprintf(a.out, "  %s %d%%\n", a.color.Cyan("Humidity:"), hour.Humidity)
 
 		if hour.PrecipProb > 0 {
 			fmt.Fprintf(a.out, "  %s %d%%\n", a.color.Cyan("Precipitation chance:"), hour.PrecipProb)
 		}
 		if hour.Precipitation > 0 {
-			fmt.Fprintf(a.out, "  %s %.1f mm\n", a.color.Cyan("Precipitation:"), hour.Precipitation)
+			fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Precipitation:"), formatPrecip(hour.Precipitation, units))
 		}
 
-		fmt.Fprintf(a.out, "  %s %.1f km/h %s\n",
+		fmt.Fprintf(a.out, "  %s %.1f %s %s\n",
 			a.color.Cyan("Wind:"),
 			hour.WindSpeed,
+			units.WindSpeedSymbol(),
 			weathercli.WindDirection(hour.WindDirection))
 		fmt.Fprintf(a.out, "  %s %d%%\n", a.color.Cyan("Cloud cover:"), hour.CloudCover)
 		fmt.Fprintln(a.out)
 	}
 }
 
+// RenderAirQuality outputs air quality in human or JSON format.
+func (a *App) RenderAirQuality(aq *weathercli.AirQuality) error {
+	if a.structured() {
+		return a.renderData(aq)
+	}
+
+	locStr := locationLabel(aq.Location)
+
+	cur := aq.Current
+	fmt.Fprintf(a.out, "%s\n", a.color.Bold(locStr))
+	fmt.Fprintf(a.out, "%s\n", a.color.Cyan(cur.Time.Format("Mon Jan 2, 2006 15:04 MST")))
+	a.renderStale(aq.Stale, aq.CachedAt)
+	fmt.Fprintln(a.out)
+
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("European AQI:"), formatEuropeanAQI(cur.EuropeanAQI, a.color))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("US AQI:"), formatUSAQI(cur.USAQI, a.color))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("PM2.5:"), formatReading(cur.PM25, "%.1f μg/m³"))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("PM10:"), formatReading(cur.PM10, "%.1f μg/m³"))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Ozone:"), formatReading(cur.Ozone, "%.1f μg/m³"))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("NO₂:"), formatReading(cur.NitrogenDioxide, "%.1f μg/m³"))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("SO₂:"), formatReading(cur.SulphurDioxide, "%.1f μg/m³"))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("CO:"), formatReading(cur.CarbonMonoxide, "%.0f μg/m³"))
+
+	if pollen := formatPollen(cur.Pollen); pollen != "" {
+		fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Pollen:"), pollen)
+	}
+
+	if len(aq.Hourly) > 0 {
+		fmt.Fprintln(a.out)
+		for _, hour := range aq.Hourly {
+			fmt.Fprintf(a.out, "%s  %s %s  %s %s  %s %s  %s %s\n",
+				a.color.Bold(hour.Time.Format("Mon Jan 2 15:04")),
+				a.color.Cyan("EU AQI"),
+				formatEuropeanAQI(hour.EuropeanAQI, a.color),
+				a.color.Cyan("US AQI"),
+				formatUSAQI(hour.USAQI, a.color),
+				a.color.Cyan("PM2.5"),
+				formatReading(hour.PM25, "%.1f"),
+				a.color.Cyan("PM10"),
+				formatReading(hour.PM10, "%.1f"))
+		}
+	}
+
+	return nil
+}
+
+// RenderMarine outputs a marine forecast in human or JSON format.
+func (a *App) RenderMarine(m *weathercli.MarineForecast) error {
+	if a.structured() {
+		return a.renderData(m)
+	}
+
+	locStr := locationLabel(m.Location)
+	length := m.Units.LengthSymbol()
+
+	cur := m.Current
+	fmt.Fprintf(a.out, "%s\n", a.color.Bold(locStr))
+	fmt.Fprintf(a.out, "%s\n", a.color.Cyan(cur.Time.Format("Mon Jan 2, 2006 15:04 MST")))
+	a.renderStale(m.Stale, m.CachedAt)
+	fmt.Fprintln(a.out)
+
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Waves:"),
+		formatWaves("%.1f %s", cur.WaveHeight, cur.WavePeriod, cur.WaveDirection, length))
+	fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Swell:"),
+		formatWaves("%.1f %s", cur.SwellWaveHeight, cur.SwellWavePeriod, cur.SwellWaveDirection, length))
+	if cur.SeaSurfaceTemp != nil {
+		fmt.Fprintf(a.out, "%s %s\n", a.color.Bold("Sea temperature:"), formatTemp(*cur.SeaSurfaceTemp, m.Units, a.color))
+	}
+	if cur.SeaLevelHeight != nil {
+		fmt.Fprintf(a.out, "%s %+.2f %s\n", a.color.Bold("Sea level:"), *cur.SeaLevelHeight, length)
+	}
+	fmt.Fprintln(a.out)
+
+	for _, day := range m.Daily {
+		fmt.Fprintf(a.out, "%s\n", a.color.Bold(day.Date.Format("Mon Jan 2")))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Waves:"),
+			formatWaves("up to %.1f %s", day.WaveHeightMax, day.WavePeriodMax, day.WaveDirection, length))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Swell:"),
+			formatWaves("up to %.1f %s", day.SwellWaveHeightMax, day.SwellWavePeriodMax, day.SwellWaveDirection, length))
+		fmt.Fprintln(a.out)
+	}
+
+	for _, hour := range m.Hourly {
+		fmt.Fprintf(a.out, "%s\n", a.color.Bold(hour.Time.Format("Mon Jan 2 15:04")))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Waves:"),
+			formatWaves("%.1f %s", hour.WaveHeight, hour.WavePeriod, hour.WaveDirection, length))
+		fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Swell:"),
+			formatWaves("%.1f %s", hour.SwellWaveHeight, hour.SwellWavePeriod, hour.SwellWaveDirection, length))
+		if hour.SeaSurfaceTemp != nil {
+			fmt.Fprintf(a.out, "  %s %s\n", a.color.Cyan("Sea temperature:"), formatTemp(*hour.SeaSurfaceTemp, m.Units, a.color))
+		}
+		if hour.SeaLevelHeight != nil {
+			fmt.Fprintf(a.out, "  %s %+.2f %s\n", a.color.Cyan("Sea level:"), *hour.SeaLevelHeight, length)
+		}
+		fmt.Fprintln(a.out)
+	}
+
+	return nil
+}
+
+// formatWaves formats a wave height (with heightFormat), period and
+// direction, leaving out the values the API had no data for.
+func formatWaves(heightFormat string, height, period *float64, direction *int, length string) string {
+	var parts []string
+	if height != nil {
+		parts = append(parts, fmt.Sprintf(heightFormat, *height, length))
+	}
+	if period != nil {
+		parts = append(parts, fmt.Sprintf("%.1f s", *period))
+	}
+	s := strings.Join(parts, ", ")
+	if direction != nil {
+		s = strings.TrimSpace(s + " from " + weathercli.WindDirection(*direction))
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // RenderLocations outputs location search results.
 func (a *App) RenderLocations(locations []weathercli.Location) error {
-	if a.json {
-		return json.NewEncoder(a.out).Encode(locations)
+	if a.structured() {
+		return a.renderData(locations)
 	}
 
 	for i, loc := range locations {
@@ -174,23 +292,126 @@ func (a *App) RenderLocations(locations []weathercli.Location) error {
 	return nil
 }
 
-// formatTemp colors temperature based on value.
-func formatTemp(temp float64, c Color) string {
-	str := fmt.Sprintf("%.1f°C", temp)
+// locationLabel returns "Name, Admin1, Country", skipping empty parts.
+func locationLabel(loc weathercli.Location) string {
+	label := loc.Name
+	if loc.Admin1 != "" {
+		label += ", " + loc.Admin1
+	}
+	if loc.Country != "" {
+		label += ", " + loc.Country
+	}
+	return label
+}
+
+// formatTemp colors temperature based on value. Thresholds are in °C
+// regardless of the display unit.
+func formatTemp(temp float64, units weathercli.Units, c Color) string {
+	return colorTemp(fmt.Sprintf("%.1f%s", temp, units.TemperatureSymbol()), temp, units, c)
+}
+
+// colorTemp colors str by the temperature temp, like formatTemp.
+func colorTemp(str string, temp float64, units weathercli.Units, c Color) string {
+	celsius := units.ToCelsius(temp)
 	switch {
-	case temp >= 30:
+	case celsius >= 30:
 		return c.Red(str)
-	case temp >= 20:
+	case celsius >= 20:
 		return c.Yellow(str)
-	case temp >= 10:
+	case celsius >= 10:
 		return c.Green(str)
-	case temp >= 0:
+	case celsius >= 0:
 		return c.Cyan(str)
 	default:
 		return c.Blue(str)
 	}
 }
 
+// formatPrecip formats a precipitation amount with its unit. Inches need
+// more precision than millimeters to stay meaningful.
+func formatPrecip(amount float64, units weathercli.Units) string {
+	if units.Precipitation == weathercli.Inches {
+		return fmt.Sprintf("%.2f in", amount)
+	}
+	return fmt.Sprintf("%.1f mm", amount)
+}
+
+// formatReading formats an air quality reading, or "n/a" if there is none.
+func formatReading(v *float64, format string) string {
+	if v == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf(format, *v)
+}
+
+// formatEuropeanAQI colors a European AQI value by band.
+func formatEuropeanAQI(p *int, c Color) string {
+	if p == nil {
+		return "n/a"
+	}
+	aqi := *p
+	str := fmt.Sprintf("%d (%s)", aqi, weathercli.EuropeanAQILevel(aqi))
+	switch {
+	case aqi <= 20:
+		return c.Green(str)
+	case aqi <= 40:
+		return c.Cyan(str)
+	case aqi <= 60:
+		return c.Yellow(str)
+	case aqi <= 80:
+		return c.Red(str)
+	default:
+		return c.Magenta(str)
+	}
+}
+
+// formatUSAQI colors a US AQI value by category.
+func formatUSAQI(p *int, c Color) string {
+	if p == nil {
+		return "n/a"
+	}
+	aqi := *p
+	str := fmt.Sprintf("%d (%s)", aqi, weathercli.USAQILevel(aqi))
+	switch {
+	case aqi <= 50:
+		return c.Green(str)
+	case aqi <= 100:
+		return c.Yellow(str)
+	case aqi <= 200:
+		return c.Red(str)
+	default:
+		return c.Magenta(str)
+	}
+}
+
+// formatPollen lists the modelled pollen species, or "" if none are.
+func formatPollen(p *weathercli.Pollen) string {
+	if p == nil {
+		return ""
+	}
+	species := []struct {
+		name  string
+		value *float64
+	}{
+		{"alder", p.Alder},
+		{"birch", p.Birch},
+		{"grass", p.Grass},
+		{"mugwort", p.Mugwort},
+		{"olive", p.Olive},
+		{"ragweed", p.Ragweed},
+	}
+	var parts []string
+	for _, s := range species {
+		if s.value != nil {
+			parts = append(parts, fmt.Sprintf("%s %.0f", s.name, *s.value))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ", ") + " grains/m³"
+}
+
 // formatUVLevel returns UV index level description.
 func formatUVLevel(uv float64) string {
 	switch {
@@ -207,6 +428,27 @@ func formatUVLevel(uv float64) string {
 	}
 }
 
+// renderStale labels data served from an expired cache entry.
+func (a *App) renderStale(stale bool, cachedAt *time.Time) {
+	if !stale || cachedAt == nil {
+		return
+	}
+	label := fmt.Sprintf("Stale: cached %s ago (offline)", formatAge(time.Since(*cachedAt)))
+	fmt.Fprintf(a.out, "%s\n", a.color.Yellow(label))
+}
+
+// formatAge formats a duration coarsely, e.g. "5m", "3h" or "2d".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
 // renderVerbose outputs verbose messages.
 func (a *App) renderVerbose(format string, args ...interface{}) {
 	fmt.Fprintf(a.err, a.color.Cyan("→ ")+format+"\n", args...)
@@ -4,38 +4,115 @@ import "time"
 
 // Root defines the CLI command tree.
 type Root struct {
-	Global   GlobalOptions `embed:""`
-	Current  CurrentCmd    `cmd:"" help:"Get current weather for a location."`
-	Forecast ForecastCmd   `cmd:"" help:"Get weather forecast for a location."`
-	Search   SearchCmd     `cmd:"" help:"Search for location coordinates."`
+	Global     GlobalOptions `embed:""`
+	Current    CurrentCmd    `cmd:"" help:"Get current weather for a location."`
+	Forecast   ForecastCmd   `cmd:"" help:"Get weather forecast for a location."`
+	Compare    CompareCmd    `cmd:"" help:"Compare current weather and daily forecasts for several locations side by side."`
+	Chart      ChartCmd      `cmd:"" help:"Chart an hourly series (temperature, precip, wind, pressure) in the terminal."`
+	Search     SearchCmd     `cmd:"" help:"Search for location coordinates."`
+	History    HistoryCmd    `cmd:"" help:"Get historical weather for a location."`
+	Air        AirCmd        `cmd:"" help:"Get air quality and pollen for a location."`
+	Marine     MarineCmd     `cmd:"" help:"Get marine forecast (waves, swell, sea temperature) for a location."`
+	Alert      AlertCmd      `cmd:"" help:"Check forecast threshold rules; exits 3 if any rule fires."`
+	Serve      ServeCmd      `cmd:"" help:"Serve current weather, forecasts and search as a cached JSON API over HTTP."`
+	Exporter   ExporterCmd   `cmd:"" help:"Export current weather for locations as Prometheus metrics."`
+	MCP        MCPCmd        `cmd:"" name:"mcp" help:"Serve weather tools to LLM agents over the Model Context Protocol (stdio)."`
+	Schema     SchemaCmd     `cmd:"" help:"Print the JSON Schema of an output (current, forecast, search, error)."`
+	Locations  LocationsCmd  `cmd:"" help:"Manage saved locations, used as @name."`
+	Completion CompletionCmd `cmd:"" help:"Print a shell completion script (bash, zsh, fish)."`
+	Config     ConfigCmd     `cmd:"" help:"Show or edit settings in the config file."`
 }
 
 // GlobalOptions are flags shared by all commands.
 type GlobalOptions struct {
-	BaseURL    string        `help:"Weather API base URL." env:"WEATHER_BASE_URL" default:"https://api.open-meteo.com/v1"`
-	GeoBaseURL string        `help:"Geocoding API base URL." env:"WEATHER_GEO_BASE_URL" default:"https://geocoding-api.open-meteo.com/v1"`
-	Timeout    time.Duration `help:"HTTP timeout." default:"10s"`
-	JSON       bool          `help:"Output JSON."`
-	NoColor    bool          `help:"Disable color output."`
-	Verbose    bool          `help:"Verbose logging."`
-	Version    VersionFlag   `name:"version" help:"Print version and exit."`
+	BaseURL      string        `help:"Weather API base URL." env:"WEATHER_BASE_URL" default:"https://api.open-meteo.com/v1"`
+	GeoBaseURL   string        `help:"Geocoding API base URL." env:"WEATHER_GEO_BASE_URL" default:"https://geocoding-api.open-meteo.com/v1"`
+	ArchiveURL   string        `name:"archive-base-url" help:"Historical weather API base URL." env:"WEATHER_ARCHIVE_BASE_URL" default:"https://archive-api.open-meteo.com/v1"`
+	AirURL       string        `name:"air-quality-base-url" help:"Air quality API base URL." env:"WEATHER_AIR_QUALITY_BASE_URL" default:"https://air-quality-api.open-meteo.com/v1"`
+	MarineURL    string        `name:"marine-base-url" help:"Marine API base URL." env:"WEATHER_MARINE_BASE_URL" default:"https://marine-api.open-meteo.com/v1"`
+	EnsembleURL  string        `name:"ensemble-base-url" help:"Ensemble API base URL." env:"WEATHER_ENSEMBLE_BASE_URL" default:"https://ensemble-api.open-meteo.com/v1"`
+	Timeout      time.Duration `help:"HTTP timeout." default:"10s"`
+	Retries      int           `help:"Retries after network errors, 429 and 5xx responses." default:"2"`
+	Units        string        `help:"Unit system (metric, imperial, custom)." enum:"metric,imperial,custom" default:"metric"`
+	Temp         string        `help:"Temperature unit override (c, f)." placeholder:"UNIT"`
+	Wind         string        `help:"Wind speed unit override (kmh, ms, mph, kn)." placeholder:"UNIT"`
+	Precip       string        `help:"Precipitation unit override (mm, inch)." placeholder:"UNIT"`
+	CacheTTL     time.Duration `help:"How long weather responses are cached." default:"10m"`
+	NoCache      bool          `help:"Disable the response cache."`
+	Offline      bool          `help:"Serve cached data only, even if expired."`
+	Format       string        `help:"Output format (human, json, ndjson, csv, tsv, yaml)." enum:"human,json,ndjson,csv,tsv,yaml" default:"human"`
+	JSON         bool          `help:"Alias for --format json."`
+	NDJSON       bool          `name:"ndjson" help:"Alias for --format ndjson."`
+	Template     string        `placeholder:"TEMPLATE" help:"Render output with a Go template, e.g. '{{.Location.Name}}: {{.Temperature}}°'."`
+	TemplateFile string        `name:"template-file" type:"existingfile" placeholder:"FILE" help:"Render output with the Go template in FILE."`
+	Table        bool          `aliases:"compact" help:"Compact forecast layout: one row per day or hour."`
+	NoColor      bool          `help:"Disable color output."`
+	Theme        string        `help:"Color theme (default, bright, mono)." enum:"default,bright,mono" default:"default"`
+	Verbose      bool          `help:"Verbose logging."`
+	Version      VersionFlag   `name:"version" help:"Print version and exit."`
 }
 
 // CurrentCmd gets current weather.
 type CurrentCmd struct {
-	Location string `arg:"" name:"location" help:"Location name (e.g. 'New York', 'London, UK')."`
+	Locations     []string `arg:"" optional:"" name:"location" sep:"none" env:"WEATHER_LOCATION" default:"${default_location}" help:"Location names, coordinates or id:N (e.g. 'New York', 'London, UK')."`
+	LocationFlags `embed:""`
+	FromFile      string        `name:"from-file" placeholder:"FILE" help:"Read locations from FILE, one per line ('-' for stdin)."`
+	Watch         time.Duration `placeholder:"INTERVAL" help:"Refresh every INTERVAL (e.g. 5m) until interrupted."`
 }
 
 // ForecastCmd gets weather forecast.
 type ForecastCmd struct {
-	Location string `arg:"" name:"location" help:"Location name (e.g. 'Paris', 'Tokyo, Japan')."`
-	Days     int    `help:"Number of forecast days (1-16)." default:"7"`
-	Hourly   bool   `help:"Show hourly forecast instead of daily."`
-	Hours    int    `help:"Number of hours for hourly forecast (1-384)." default:"24"`
+	Locations     []string `arg:"" optional:"" name:"location" sep:"none" env:"WEATHER_LOCATION" default:"${default_location}" help:"Location names, coordinates or id:N (e.g. 'Paris', 'Tokyo, Japan')."`
+	LocationFlags `embed:""`
+	FromFile      string        `name:"from-file" placeholder:"FILE" help:"Read locations from FILE, one per line ('-' for stdin)."`
+	Watch         time.Duration `placeholder:"INTERVAL" help:"Refresh every INTERVAL (e.g. 5m) until interrupted."`
+	Days          int           `help:"Number of forecast days (1-16)." default:"7"`
+	Hourly        bool          `help:"Show hourly forecast instead of daily."`
+	Hours         int           `help:"Number of hours for hourly forecast (1-384)." default:"24"`
+
+	Ensemble        bool     `help:"Show the spread of ensemble members (mean, 10th-90th percentile, min-max) instead of a single forecast."`
+	Models          []string `placeholder:"MODEL,..." help:"Ensemble models to combine with --ensemble (e.g. icon_seamless,gfs_seamless,ecmwf_ifs025; default icon_seamless)."`
+	PrecipThreshold float64  `name:"precip-threshold" placeholder:"AMOUNT" help:"Precipitation a member must exceed to count toward --ensemble precipitation chance, per hour or day (default 0.1 mm / 0.01 inch)."`
 }
 
 // SearchCmd searches for locations.
 type SearchCmd struct {
-	Query string `arg:"" name:"query" help:"Location search query."`
-	Limit int    `help:"Max results (1-10)." default:"5"`
+	Query   string `arg:"" name:"query" help:"Location search query."`
+	Limit   int    `help:"Max results (1-100)." default:"5"`
+	Country string `help:"Only match locations in this country (ISO code, e.g. US)." placeholder:"CC"`
+	Admin   string `help:"Only match locations in this state or region (e.g. 'Oregon')." placeholder:"REGION"`
+}
+
+// HistoryCmd gets historical weather.
+type HistoryCmd struct {
+	Location      string `arg:"" optional:"" name:"location" env:"WEATHER_LOCATION" default:"${default_location}" help:"Location name, coordinates or id:N (e.g. 'Lisbon', 'Denver, Colorado')."`
+	LocationFlags `embed:""`
+	From          string `help:"Start date (YYYY-MM-DD)." required:""`
+	To            string `help:"End date (YYYY-MM-DD)." required:""`
+	Hourly        bool   `help:"Show hourly history instead of daily."`
+}
+
+// AirCmd gets air quality.
+type AirCmd struct {
+	Location      string `arg:"" optional:"" name:"location" env:"WEATHER_LOCATION" default:"${default_location}" help:"Location name, coordinates or id:N (e.g. 'Delhi', 'Krakow, Poland')."`
+	LocationFlags `embed:""`
+	Hours         int `help:"Number of hours of hourly forecast (0-168)." default:"24"`
+}
+
+// MarineCmd gets marine forecast.
+type MarineCmd struct {
+	Location      string `arg:"" optional:"" name:"location" env:"WEATHER_LOCATION" default:"${default_location}" help:"Coastal location name, coordinates or id:N (e.g. 'Biarritz', 'Cape Town')."`
+	LocationFlags `embed:""`
+	Days          int  `help:"Number of forecast days (1-16)." default:"7"`
+	Hourly        bool `help:"Show hourly forecast instead of daily."`
+	Hours         int  `help:"Number of hours for hourly forecast (1-384)." default:"24"`
+}
+
+// AlertCmd evaluates threshold rules against a forecast.
+type AlertCmd struct {
+	Location      string `arg:"" optional:"" name:"location" env:"WEATHER_LOCATION" default:"${default_location}" help:"Location name, coordinates or id:N (e.g. 'Oslo', 'Denver, Colorado')."`
+	LocationFlags `embed:""`
+	Rules         []string `name:"rule" short:"r" sep:"none" help:"Rule such as 'temp_min < 0' or 'precip_prob >= 70 within 12h'. Repeatable."`
+	RulesFile     string   `name:"rules-file" type:"existingfile" help:"File with one rule per line ('#' starts a comment)."`
+	Days          int      `help:"Number of forecast days to check (1-16)." default:"7"`
 }
@@ -2,9 +2,17 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/pjtf93/weathercli"
@@ -12,12 +20,22 @@ import (
 
 // App wires CLI output and API access.
 type App struct {
-	client  *weathercli.Client
-	out     io.Writer
-	err     io.Writer
-	json    bool
-	color   Color
-	verbose bool
+	client      *weathercli.Client
+	in          io.Reader
+	out         io.Writer
+	err         io.Writer
+	format      string             // --format: human, json, ndjson, csv, tsv or yaml, or template
+	template    *template.Template // --template or --template-file
+	emitted     bool               // structured output already written (by --watch)
+	color       Color
+	verbose     bool
+	interactive bool   // stdin and stderr are terminals, so prompts can be shown
+	tty         bool   // stdout is a terminal, so --watch can redraw in place
+	width       int    // columns to fit tables to; 0 if unknown
+	table       bool   // compact forecast layout
+	savedPath   string // saved locations file; "" if there is no config directory
+	config      *weathercli.Config
+	configPath  string
 }
 
 // Run executes the CLI with the provided arguments.
@@ -29,6 +47,17 @@ func Run(args []string, stdout io.Writer, stderr io.Writer) int {
 		stderr = os.Stderr
 	}
 
+	configPath, _ := weathercli.DefaultConfigPath()
+	config := weathercli.NewConfig()
+	if configPath != "" {
+		var err error
+		if config, err = weathercli.LoadConfig(configPath); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	defaultLocation, _ := config.Get(configLocationKey)
+
 	root := Root{}
 	exitCode := 0
 	parser, err := kong.New(
@@ -42,7 +71,8 @@ func Run(args []string, stdout io.Writer, stderr io.Writer) int {
 			exitCode = code
 			panic(exitSignal{code: code})
 		}),
-		kong.Vars{"version": Version},
+		kong.Vars{"version": Version, "default_location": defaultLocation},
+		kong.Resolvers(configResolver{path: configPath, cfg: config}),
 	)
 	if err != nil {
 		_, _ = fmt.Fprintln(stderr, err)
@@ -54,43 +84,127 @@ func Run(args []string, stdout io.Writer, stderr io.Writer) int {
 		return exitCode
 	}
 	if err != nil {
+		wantsJSON := root.Global.wantsJSON()
 		if parseErr, ok := err.(*kong.ParseError); ok {
-			_ = parseErr.Context.PrintUsage(true)
-			_, _ = fmt.Fprintln(stderr, parseErr.Error())
-			return 2
+			wantsJSON = parsedGlobals(parseErr.Context).wantsJSON()
+			if !wantsJSON {
+				_ = parseErr.Context.PrintUsage(true)
+			}
 		}
-		_, _ = fmt.Fprintln(stderr, err)
-		return 2
+		return usageError(stderr, wantsJSON, err)
 	}
 
-	if root.Global.JSON {
-		// JSON output should never include ANSI escapes.
+	format, err := outputFormat(root.Global)
+	if err != nil {
+		return usageError(stderr, root.Global.wantsJSON(), err)
+	}
+	tmpl, err := parseTemplate(root.Global)
+	if err != nil {
+		return usageError(stderr, jsonErrors(format), err)
+	}
+	if format != formatHuman {
+		// Structured output should never include ANSI escapes.
 		root.Global.NoColor = true
 	}
 
-	client := weathercli.NewClient(weathercli.Options{
-		BaseURL:    root.Global.BaseURL,
-		GeoBaseURL: root.Global.GeoBaseURL,
-		Timeout:    root.Global.Timeout,
-	})
+	units, err := weathercli.ParseUnits(root.Global.Units, root.Global.Temp, root.Global.Wind, root.Global.Precip)
+	if err != nil {
+		return usageError(stderr, jsonErrors(format), err)
+	}
+
+	if root.Global.Offline && root.Global.NoCache {
+		return usageError(stderr, jsonErrors(format), errors.New("--offline requires the cache; drop --no-cache"))
+	}
+
+	var cacheDir string
+	if !root.Global.NoCache {
+		// Without a cache directory, run uncached rather than fail.
+		cacheDir, _ = weathercli.DefaultCacheDir()
+	}
+
+	var savedPath string
+	if configDir, err := weathercli.DefaultConfigDir(); err == nil {
+		savedPath = filepath.Join(configDir, "locations.json")
+	}
 
 	app := &App{
-		client:  client,
-		out:     stdout,
-		err:     stderr,
-		json:    root.Global.JSON,
-		color:   NewColor(colorEnabled(root.Global.NoColor)),
-		verbose: root.Global.Verbose,
+		config:     config,
+		configPath: configPath,
+		savedPath:  savedPath,
+		in:         os.Stdin,
+		out:        stdout,
+		err:        stderr,
+		format:     format,
+		template:   tmpl,
+		table:      root.Global.Table,
+		color:      NewThemedColor(colorEnabled(root.Global.NoColor), root.Global.Theme),
+		verbose:    root.Global.Verbose,
 	}
+	if f, ok := stderr.(*os.File); ok {
+		app.interactive = isTerminal(os.Stdin) && isTerminal(f)
+	}
+	if f, ok := stdout.(*os.File); ok {
+		app.tty = isTerminal(f)
+		app.width = outputWidth(f, app.tty)
+	}
+
+	retryPolicy := weathercli.RetryPolicy{}
+	if app.verbose {
+		retryPolicy.OnRetry = func(attempt int, delay time.Duration, err error) {
+			app.renderVerbose("Retry %d/%d in %s: %v", attempt, root.Global.Retries, delay.Round(time.Millisecond), err)
+		}
+	}
+
+	app.client = weathercli.NewClient(weathercli.Options{
+		BaseURL:           root.Global.BaseURL,
+		GeoBaseURL:        root.Global.GeoBaseURL,
+		ArchiveBaseURL:    root.Global.ArchiveURL,
+		AirQualityBaseURL: root.Global.AirURL,
+		MarineBaseURL:     root.Global.MarineURL,
+		EnsembleBaseURL:   root.Global.EnsembleURL,
+		Timeout:           root.Global.Timeout,
+		Units:             units,
+		CacheDir:          cacheDir,
+		CacheTTL:          root.Global.CacheTTL,
+		Offline:           root.Global.Offline,
+		MaxRetries:        root.Global.Retries,
+		RetryPolicy:       retryPolicy,
+	})
+
+	// Ctrl-C cancels in-flight requests and ends --watch.
+	runCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	ctx.Bind(app)
+	ctx.BindTo(runCtx, (*context.Context)(nil))
 	if err := ctx.Run(); err != nil {
-		return handleError(stderr, app.color, err)
+		return handleError(stderr, app.color, jsonErrors(format), err)
 	}
 
 	return 0
 }
 
+// parsedGlobals returns the output flags kctx parsed before an error;
+// they are not applied to Root when parsing fails.
+func parsedGlobals(kctx *kong.Context) GlobalOptions {
+	var g GlobalOptions
+	for _, f := range kctx.Flags() {
+		switch v := kctx.FlagValue(f).(type) {
+		case bool:
+			if f.Name == "json" {
+				g.JSON = v
+			} else if f.Name == "ndjson" {
+				g.NDJSON = v
+			}
+		case string:
+			if f.Name == "format" {
+				g.Format = v
+			}
+		}
+	}
+	return g
+}
+
 type exitSignal struct {
 	code int
 }
@@ -111,19 +225,113 @@ func parseWithExit(parser *kong.Kong, args []string, exitCode *int) (ctx *kong.C
 	return ctx, false, err
 }
 
-func handleError(w io.Writer, c Color, err error) int {
-	fmt.Fprintf(w, "%s %v\n", c.Red("Error:"), err)
-	return 1
+// exitError ends the command with a specific exit code. It carries no
+// message: the command has already reported the outcome.
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// Exit codes for failures, so scripts can tell them apart. They are
+// stable; 3 is exitCodeAlertsFired.
+const (
+	exitCodeError       = 1
+	exitCodeUsage       = 2
+	exitCodeNotFound    = 4
+	exitCodeNetwork     = 5
+	exitCodeAPI         = 6
+	exitCodeRateLimited = 7
+	exitCodeBadResponse = 8
+)
+
+// handleError reports err on w, as an ErrorResponse line when jsonErrors
+// is set, and returns its exit code.
+func handleError(w io.Writer, c Color, jsonErrors bool, err error) int {
+	var exitErr exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	if jsonErrors {
+		writeErrorJSON(w, err)
+	} else {
+		fmt.Fprintf(w, "%s %v\n", c.Red("Error:"), err)
+	}
+	return exitCode(err)
+}
+
+// writeErrorJSON writes err as an ErrorResponse line.
+func writeErrorJSON(w io.Writer, err error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(weathercli.NewErrorResponse(err))
+}
+
+// usageError reports a command-line error and returns exitCodeUsage.
+func usageError(w io.Writer, jsonErrors bool, err error) int {
+	if jsonErrors {
+		return handleError(w, Color{}, true, &weathercli.UsageError{Err: err})
+	}
+	fmt.Fprintln(w, err)
+	return exitCodeUsage
+}
+
+// usagef returns an error for invalid flags or arguments, which exits with
+// exitCodeUsage.
+func usagef(format string, args ...any) error {
+	return &weathercli.UsageError{Err: fmt.Errorf(format, args...)}
+}
+
+// exitCodes maps error codes to exit codes.
+var exitCodes = map[string]int{
+	weathercli.ErrorCodeInvalidRequest:   exitCodeUsage,
+	weathercli.ErrorCodeLocationNotFound: exitCodeNotFound,
+	weathercli.ErrorCodeNoMarineData:     exitCodeNotFound,
+	weathercli.ErrorCodeNetwork:          exitCodeNetwork,
+	weathercli.ErrorCodeOffline:          exitCodeNetwork,
+	weathercli.ErrorCodeAPI:              exitCodeAPI,
+	weathercli.ErrorCodeRateLimited:      exitCodeRateLimited,
+	weathercli.ErrorCodeBadResponse:      exitCodeBadResponse,
+}
+
+// exitCode maps library errors to exit codes.
+func exitCode(err error) int {
+	if code, ok := exitCodes[weathercli.NewErrorResponse(err).Error.Code]; ok {
+		return code
+	}
+	return exitCodeError
 }
 
 // Run for CurrentCmd.
-func (c *CurrentCmd) Run(app *App) error {
+func (c *CurrentCmd) Run(app *App, ctx context.Context) error {
+	queries, err := app.locationQueries(c.Locations, c.FromFile)
+	if err != nil {
+		return err
+	}
+	if err := checkWatch(c.Watch, len(queries)); err != nil {
+		return err
+	}
+	if len(queries) > 1 {
+		return c.runMany(ctx, app, queries)
+	}
+	query := strings.Join(queries, "")
+
 	if app.verbose {
-		app.renderVerbose("Fetching current weather for: %s", c.Location)
+		app.renderVerbose("Fetching current weather for: %s", query)
+	}
+
+	loc, err := app.resolveLocation(ctx, query, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+	if c.Watch > 0 {
+		return c.watch(ctx, app, loc)
 	}
 
-	ctx := context.Background()
-	weather, err := app.client.Current(ctx, c.Location)
+	weather, err := app.client.CurrentByCoords(ctx, loc.Latitude, loc.Longitude, &loc)
 	if err != nil {
 		return err
 	}
@@ -132,7 +340,7 @@ func (c *CurrentCmd) Run(app *App) error {
 }
 
 // Run for ForecastCmd.
-func (c *ForecastCmd) Run(app *App) error {
+func (c *ForecastCmd) Run(app *App, ctx context.Context) error {
 	days := c.Days
 	if c.Hourly && c.Hours > 0 {
 		// Convert hours to days for API
@@ -143,19 +351,51 @@ func (c *ForecastCmd) Run(app *App) error {
 	}
 
 	if days < 1 || days > 16 {
-		return fmt.Errorf("days must be between 1 and 16")
+		return usagef("days must be between 1 and 16")
+	}
+
+	if !c.Ensemble && (len(c.Models) > 0 || c.PrecipThreshold != 0) {
+		return usagef("--models and --precip-threshold require --ensemble")
+	}
+	if c.PrecipThreshold < 0 {
+		return usagef("--precip-threshold must not be negative")
+	}
+
+	queries, err := app.locationQueries(c.Locations, c.FromFile)
+	if err != nil {
+		return err
+	}
+	if err := checkWatch(c.Watch, len(queries)); err != nil {
+		return err
 	}
+	if c.Ensemble && (len(queries) > 1 || c.Watch > 0) {
+		return usagef("--ensemble takes a single location and no --watch")
+	}
+	if len(queries) > 1 {
+		return c.runMany(ctx, app, queries, days)
+	}
+	query := strings.Join(queries, "")
 
 	if app.verbose {
 		if c.Hourly {
-			app.renderVerbose("Fetching %d-hour forecast for: %s", c.Hours, c.Location)
+			app.renderVerbose("Fetching %d-hour forecast for: %s", c.Hours, query)
 		} else {
-			app.renderVerbose("Fetching %d-day forecast for: %s", days, c.Location)
+			app.renderVerbose("Fetching %d-day forecast for: %s", days, query)
 		}
 	}
 
-	ctx := context.Background()
-	forecast, err := app.client.Forecast(ctx, c.Location, days, c.Hourly)
+	loc, err := app.resolveLocation(ctx, query, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+	if c.Watch > 0 {
+		return c.watch(ctx, app, loc, days)
+	}
+	if c.Ensemble {
+		return c.runEnsemble(ctx, app, loc, days)
+	}
+
+	forecast, err := app.client.ForecastByCoords(ctx, loc.Latitude, loc.Longitude, days, c.Hourly, &loc)
 	if err != nil {
 		return err
 	}
@@ -169,20 +409,136 @@ func (c *ForecastCmd) Run(app *App) error {
 }
 
 // Run for SearchCmd.
-func (c *SearchCmd) Run(app *App) error {
+func (c *SearchCmd) Run(app *App, ctx context.Context) error {
 	if app.verbose {
 		app.renderVerbose("Searching locations: %s", c.Query)
 	}
 
-	ctx := context.Background()
-	locations, err := app.client.SearchLocation(ctx, c.Query)
+	if name, ok := strings.CutPrefix(c.Query, "@"); ok {
+		loc, err := app.savedLocation(name)
+		if err != nil {
+			return err
+		}
+		return app.RenderLocations([]weathercli.Location{loc})
+	}
+
+	locations, err := app.client.SearchLocation(ctx, c.Query, weathercli.SearchOptions{
+		Count:       c.Limit,
+		CountryCode: c.Country,
+		Admin:       c.Admin,
+	})
 	if err != nil {
 		return err
 	}
 
-	if c.Limit > 0 && len(locations) > c.Limit {
-		locations = locations[:c.Limit]
+	return app.RenderLocations(locations)
+}
+
+// Run for HistoryCmd.
+func (c *HistoryCmd) Run(app *App, ctx context.Context) error {
+	from, err := time.Parse("2006-01-02", c.From)
+	if err != nil {
+		return usagef("invalid --from date %q (want YYYY-MM-DD)", c.From)
+	}
+	to, err := time.Parse("2006-01-02", c.To)
+	if err != nil {
+		return usagef("invalid --to date %q (want YYYY-MM-DD)", c.To)
+	}
+	if to.Before(from) {
+		return usagef("--to date must not be before --from date")
 	}
 
-	return app.RenderLocations(locations)
+	if app.verbose {
+		app.renderVerbose("Fetching history for: %s (%s to %s)", c.Location, c.From, c.To)
+	}
+
+	loc, err := app.resolveLocation(ctx, c.Location, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+
+	history, err := app.client.History(ctx, loc, from, to, weathercli.HistoryOptions{Hourly: c.Hourly})
+	if err != nil {
+		return err
+	}
+
+	return app.RenderForecast(history)
+}
+
+// Run for AirCmd.
+func (c *AirCmd) Run(app *App, ctx context.Context) error {
+	if c.Hours < 0 || c.Hours > 168 {
+		return usagef("hours must be between 0 and 168")
+	}
+	days := (c.Hours + 23) / 24
+	if days < 1 {
+		days = 1
+	}
+
+	if app.verbose {
+		app.renderVerbose("Fetching air quality for: %s", c.Location)
+	}
+
+	loc, err := app.resolveLocation(ctx, c.Location, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+
+	aq, err := app.client.AirQuality(ctx, loc.Latitude, loc.Longitude, days, &loc)
+	if err != nil {
+		return err
+	}
+
+	// Hourly data starts at midnight; keep the requested window from now on.
+	aq.Hourly = hoursFrom(aq.Hourly, aq.Current.Time, c.Hours)
+
+	return app.RenderAirQuality(aq)
+}
+
+// hoursFrom returns up to n readings starting at the hour containing t.
+func hoursFrom(readings []weathercli.AirQualityReading, t time.Time, n int) []weathercli.AirQualityReading {
+	i := 0
+	for i < len(readings) && !readings[i].Time.After(t.Add(-time.Hour)) {
+		i++
+	}
+	readings = readings[i:]
+	if len(readings) > n {
+		readings = readings[:n]
+	}
+	return readings
+}
+
+// Run for MarineCmd.
+func (c *MarineCmd) Run(app *App, ctx context.Context) error {
+	days := c.Days
+	if c.Hourly && c.Hours > 0 {
+		days = (c.Hours + 23) / 24
+		if days > 16 {
+			days = 16
+		}
+	}
+
+	if days < 1 || days > 16 {
+		return usagef("days must be between 1 and 16")
+	}
+
+	if app.verbose {
+		app.renderVerbose("Fetching marine forecast for: %s", c.Location)
+	}
+
+	loc, err := app.resolveLocation(ctx, c.Location, c.LocationFlags)
+	if err != nil {
+		return err
+	}
+
+	marine, err := app.client.Marine(ctx, loc.Latitude, loc.Longitude, days, c.Hourly, &loc)
+	if err != nil {
+		return err
+	}
+
+	if c.Hourly && len(marine.Hourly) > c.Hours {
+		marine.Hourly = marine.Hourly[:c.Hours]
+	}
+
+	return app.RenderMarine(marine)
 }
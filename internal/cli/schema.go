@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// SchemaCmd prints the JSON Schema of a JSON output.
+type SchemaCmd struct {
+	Name string `arg:"" name:"output" enum:"current,forecast,search,error" help:"Output to describe (current, forecast, search, error)."`
+}
+
+// Run for SchemaCmd.
+func (c *SchemaCmd) Run(app *App) error {
+	schema, err := weathercli.OutputSchema(c.Name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(app.out, string(data))
+	return nil
+}
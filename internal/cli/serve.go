@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish after an
+// interrupt.
+const shutdownTimeout = 10 * time.Second
+
+// ServeCmd serves the weather API over HTTP.
+type ServeCmd struct {
+	Addr string        `help:"Address to listen on." default:":8080"`
+	TTL  time.Duration `name:"ttl" help:"How long responses are cached in memory (0 to disable)." default:"5m"`
+}
+
+// Run for ServeCmd.
+func (c *ServeCmd) Run(app *App, ctx context.Context) error {
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = -1 // ServerOptions treats 0 as the default
+	}
+	opts := weathercli.ServerOptions{CacheTTL: ttl}
+	if app.verbose {
+		opts.OnRequest = func(r *http.Request, status int, cache string, elapsed time.Duration) {
+			if cache == "" {
+				cache = "-"
+			}
+			app.renderVerbose("%s %s %d %s %s", r.Method, r.URL.RequestURI(), status, cache, elapsed.Round(time.Millisecond))
+		}
+	}
+
+	ln, err := net.Listen("tcp", c.Addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           weathercli.NewServer(app.client, opts),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(app.err, "Serving on http://%s (Ctrl-C to stop)\n", ln.Addr())
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ln) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-done; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// tableColumn is one column of the compact forecast layout.
+type tableColumn struct {
+	header   string
+	priority int  // higher priorities are dropped first on narrow terminals
+	right    bool // right-align, for numbers
+	cells    []string
+	color    func(row int, s string) string // colors a padded cell; optional
+	width    int
+}
+
+// minConditionWidth is as narrow as the condition column gets before
+// columns are dropped instead.
+const minConditionWidth = 12
+
+// renderForecastTable renders a forecast with one row per day or hour.
+func (a *App) renderForecastTable(f *weathercli.Forecast) {
+	units := f.Units
+	temp := func(v float64) string { return fmt.Sprintf("%.1f%s", v, units.TemperatureSymbol()) }
+	wind := func(v float64, dir int) string {
+		return fmt.Sprintf("%.0f %s %s", v, units.WindSpeedSymbol(), weathercli.WindDirection(dir))
+	}
+	precip := func(v float64) string {
+		if units.Precipitation == weathercli.Inches {
+			return fmt.Sprintf("%.2f", v)
+		}
+		return fmt.Sprintf("%.1f", v)
+	}
+	tempColor := func(values []float64) func(int, string) string {
+		return func(row int, s string) string { return colorTemp(s, values[row], units, a.color) }
+	}
+
+	if len(f.Daily) > 0 {
+		n := len(f.Daily)
+		date := &tableColumn{header: "Day", cells: make([]string, n)}
+		cond := &tableColumn{header: "Condition", priority: 1, cells: make([]string, n)}
+		high := &tableColumn{header: "High", right: true, cells: make([]string, n)}
+		low := &tableColumn{header: "Low", right: true, cells: make([]string, n)}
+		feels := &tableColumn{header: "Feels", priority: 4, right: true, cells: make([]string, n)}
+		prob := &tableColumn{header: "Precip", priority: 1, right: true, cells: make([]string, n)}
+		amount := &tableColumn{header: units.PrecipitationSymbol(), priority: 3, right: true, cells: make([]string, n)}
+		windCol := &tableColumn{header: "Wind", priority: 2, cells: make([]string, n)}
+		uv := &tableColumn{header: "UV", priority: 5, right: true, cells: make([]string, n)}
+		sun := &tableColumn{header: "Sun", priority: 6, cells: make([]string, n)}
+		highs, lows := make([]float64, n), make([]float64, n)
+		for i, d := range f.Daily {
+			highs[i], lows[i] = d.TempMax, d.TempMin
+			date.cells[i] = d.Date.Format("Mon Jan 2")
+			cond.cells[i] = d.Condition
+			high.cells[i] = temp(d.TempMax)
+			low.cells[i] = temp(d.TempMin)
+			feels.cells[i] = fmt.Sprintf("%.0f/%.0f%s", d.ApparentMax, d.ApparentMin, units.TemperatureSymbol())
+			prob.cells[i] = fmt.Sprintf("%d%%", d.PrecipProb)
+			amount.cells[i] = precip(d.Precipitation)
+			windCol.cells[i] = wind(d.WindSpeedMax, d.WindDirection)
+			uv.cells[i] = fmt.Sprintf("%.1f", d.UVIndexMax)
+			sun.cells[i] = d.Sunrise.Format("15:04") + "-" + d.Sunset.Format("15:04")
+		}
+		high.color, low.color = tempColor(highs), tempColor(lows)
+		a.writeTable([]*tableColumn{date, cond, high, low, feels, prob, amount, windCol, uv, sun}, nil)
+	}
+
+	if len(f.Hourly) > 0 {
+		if len(f.Daily) > 0 {
+			fmt.Fprintln(a.out)
+		}
+		n := len(f.Hourly)
+		hour := &tableColumn{header: "Time", cells: make([]string, n)}
+		cond := &tableColumn{header: "Condition", priority: 1, cells: make([]string, n)}
+		tempCol := &tableColumn{header: "Temp", right: true, cells: make([]string, n)}
+		feels := &tableColumn{header: "Feels", priority: 4, right: true, cells: make([]string, n)}
+		prob := &tableColumn{header: "Precip", priority: 1, right: true, cells: make([]string, n)}
+		amount := &tableColumn{header: units.PrecipitationSymbol(), priority: 3, right: true, cells: make([]string, n)}
+		windCol := &tableColumn{header: "Wind", priority: 2, cells: make([]string, n)}
+		uv := &tableColumn{header: "UV", priority: 5, right: true, cells: make([]string, n)}
+		temps := make([]float64, n)
+		days := map[int]string{}
+		for i, h := range f.Hourly {
+			temps[i] = h.Temperature
+			if i == 0 || h.Time.YearDay() != f.Hourly[i-1].Time.YearDay() {
+				days[i] = h.Time.Format("Mon Jan 2")
+			}
+			hour.cells[i] = h.Time.Format("15:04")
+			cond.cells[i] = h.Condition
+			tempCol.cells[i] = temp(h.Temperature)
+			feels.cells[i] = temp(h.Apparent)
+			prob.cells[i] = fmt.Sprintf("%d%%", h.PrecipProb)
+			amount.cells[i] = precip(h.Precipitation)
+			windCol.cells[i] = wind(h.WindSpeed, h.WindDirection)
+			uv.cells[i] = fmt.Sprintf("%.1f", h.UVIndex)
+		}
+		tempCol.color = tempColor(temps)
+		a.renderSparkline(f.Hourly, units)
+		a.writeTable([]*tableColumn{hour, cond, tempCol, feels, prob, amount, windCol, uv}, days)
+	}
+}
+
+// writeTable writes aligned columns, fitted to a.width: low-priority
+// columns are dropped and the condition column is truncated as needed.
+// separators maps row indexes to section headings printed before them.
+func (a *App) writeTable(columns []*tableColumn, separators map[int]string) {
+	for _, c := range columns {
+		c.width = utf8.RuneCountInString(c.header)
+		for _, cell := range c.cells {
+			c.width = max(c.width, utf8.RuneCountInString(cell))
+		}
+	}
+	columns = fitColumns(columns, a.width)
+
+	var header []string
+	for _, c := range columns {
+		header = append(header, alignCell(c.header, c.width, c.right))
+	}
+	fmt.Fprintln(a.out, a.color.Bold(strings.TrimRight(strings.Join(header, "  "), " ")))
+
+	rows := len(columns[0].cells)
+	for row := 0; row < rows; row++ {
+		if label, ok := separators[row]; ok {
+			fmt.Fprintln(a.out, a.color.Cyan(label))
+		}
+		var line []string
+		for i, c := range columns {
+			cell := truncate(c.cells[row], c.width)
+			width := c.width
+			if i == len(columns)-1 && !c.right {
+				width = 0 // no trailing padding
+			}
+			cell = alignCell(cell, width, c.right)
+			if c.color != nil {
+				cell = c.color(row, cell)
+			}
+			line = append(line, cell)
+		}
+		fmt.Fprintln(a.out, strings.Join(line, "  "))
+	}
+}
+
+// fitColumns drops the highest-priority columns, last first, until the
+// table fits width, after first narrowing the condition column. A width of
+// 0 keeps every column.
+func fitColumns(columns []*tableColumn, width int) []*tableColumn {
+	total := func() int {
+		sum := 2 * (len(columns) - 1)
+		for _, c := range columns {
+			sum += c.width
+		}
+		return sum
+	}
+	if width <= 0 {
+		return columns
+	}
+	natural := map[*tableColumn]int{}
+	for _, c := range columns {
+		natural[c] = c.width
+	}
+	for total() > width {
+		for _, c := range columns {
+			if c.header == "Condition" && c.width > minConditionWidth {
+				c.width = max(minConditionWidth, c.width-(total()-width))
+			}
+		}
+		if total() <= width {
+			break
+		}
+		drop := -1
+		for i, c := range columns {
+			if c.priority > 0 && (drop < 0 || c.priority >= columns[drop].priority) {
+				drop = i
+			}
+		}
+		if drop < 0 {
+			break
+		}
+		columns = append(columns[:drop:drop], columns[drop+1:]...)
+		// Narrow the condition again only as far as the rest needs.
+		for _, c := range columns {
+			c.width = natural[c]
+		}
+	}
+	return columns
+}
+
+func alignCell(s string, width int, right bool) string {
+	padding := strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+	if right {
+		return padding + s
+	}
+	return s + padding
+}
+
+// truncate shortens s to width runes, marking the cut with "…".
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// outputWidth returns the width to fit tables to: $COLUMNS if set, else
+// the terminal's width, else 0 (unlimited).
+func outputWidth(f *os.File, tty bool) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if !tty {
+		return 0
+	}
+	return terminalWidth(f)
+}
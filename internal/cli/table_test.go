@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+func testTableForecast() *weathercli.Forecast {
+	tz := time.FixedZone("CET", 3600)
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	at := func(d, h, m int) time.Time { return time.Date(2024, 1, d, h, m, 0, 0, tz) }
+	return &weathercli.Forecast{
+		Units: weathercli.MetricUnits(),
+		Daily: []weathercli.DailyForecast{
+			{Date: day(12), Condition: "Slight rain", TempMax: 5.2, TempMin: -1.4, ApparentMax: 2, ApparentMin: -5, PrecipProb: 80, Precipitation: 3.25, WindSpeedMax: 18, WindDirection: 250, UVIndexMax: 0.8, Sunrise: at(12, 8, 13), Sunset: at(12, 16, 21)},
+			{Date: day(13), Condition: "Thunderstorm with heavy hail", TempMax: 12.6, TempMin: 4, ApparentMax: 11, ApparentMin: 1, PrecipProb: 5, Precipitation: 0, WindSpeedMax: 7, WindDirection: 90, UVIndexMax: 1.5, Sunrise: at(13, 8, 12), Sunset: at(13, 16, 23)},
+		},
+	}
+}
+
+func testHourlyForecast() *weathercli.Forecast {
+	tz := time.FixedZone("CET", 3600)
+	at := func(d, h int) time.Time { return time.Date(2024, 1, d, h, 0, 0, 0, tz) }
+	return &weathercli.Forecast{
+		Units: weathercli.MetricUnits(),
+		Hourly: []weathercli.HourlyForecast{
+			{Time: at(12, 22), Condition: "Overcast", Temperature: 1.5, Apparent: -2.1, PrecipProb: 10, WindSpeed: 12, WindDirection: 200},
+			{Time: at(12, 23), Condition: "Light drizzle", Temperature: 1.1, Apparent: -2.6, PrecipProb: 45, Precipitation: 0.2, WindSpeed: 14, WindDirection: 210},
+			{Time: at(13, 0), Condition: "Fog", Temperature: -0.4, Apparent: -4, PrecipProb: 30, Precipitation: 0.1, WindSpeed: 9, WindDirection: 180, UVIndex: 0},
+		},
+	}
+}
+
+func TestRenderForecastTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		forecast *weathercli.Forecast
+		width    int
+		want     string
+	}{
+		{
+			name:     "daily, unlimited",
+			forecast: testTableForecast(),
+			want: `Day         Condition                       High     Low   Feels  Precip   mm  Wind          UV  Sun
+Fri Jan 12  Slight rain                    5.2°C  -1.4°C  2/-5°C     80%  3.2  18 km/h WSW  0.8  08:13-16:21
+Sat Jan 13  Thunderstorm with heavy hail  12.6°C   4.0°C  11/1°C      5%  0.0  7 km/h E     1.5  08:12-16:23
+`,
+		},
+		{
+			// Sun is dropped, then the condition is cut to fit exactly.
+			name:     "daily, 80 columns",
+			forecast: testTableForecast(),
+			width:    80,
+			want: `Day         Condition        High     Low   Feels  Precip   mm  Wind          UV
+Fri Jan 12  Slight rain     5.2°C  -1.4°C  2/-5°C     80%  3.2  18 km/h WSW  0.8
+Sat Jan 13  Thunderstorm…  12.6°C   4.0°C  11/1°C      5%  0.0  7 km/h E     1.5
+`,
+		},
+		{
+			name:     "daily, 60 columns",
+			forecast: testTableForecast(),
+			width:    60,
+			want: `Day         Condition                   High     Low  Precip
+Fri Jan 12  Slight rain                5.2°C  -1.4°C     80%
+Sat Jan 13  Thunderstorm with heavy…  12.6°C   4.0°C      5%
+`,
+		},
+		{
+			// Columns without a priority are kept even if they overflow.
+			name:     "daily, 20 columns",
+			forecast: testTableForecast(),
+			width:    20,
+			want: `Day           High     Low
+Fri Jan 12   5.2°C  -1.4°C
+Sat Jan 13  12.6°C   4.0°C
+`,
+		},
+		{
+			name:     "hourly, unlimited",
+			forecast: testHourlyForecast(),
+			want: `Trend: █▇▁  -0.4°C – 1.5°C
+
+Time   Condition        Temp   Feels  Precip   mm  Wind          UV
+Fri Jan 12
+22:00  Overcast        1.5°C  -2.1°C     10%  0.0  12 km/h SSW  0.0
+23:00  Light drizzle   1.1°C  -2.6°C     45%  0.2  14 km/h SSW  0.0
+Sat Jan 13
+00:00  Fog            -0.4°C  -4.0°C     30%  0.1  9 km/h S     0.0
+`,
+		},
+		{
+			name:     "hourly, 50 columns",
+			forecast: testHourlyForecast(),
+			width:    50,
+			want: `Trend: █▇▁  -0.4°C – 1.5°C
+
+Time   Condition        Temp  Precip  Wind
+Fri Jan 12
+22:00  Overcast        1.5°C     10%  12 km/h SSW
+23:00  Light drizzle   1.1°C     45%  14 km/h SSW
+Sat Jan 13
+00:00  Fog            -0.4°C     30%  9 km/h S
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		var out strings.Builder
+		app := &App{out: &out, color: NewColor(false), width: tt.width}
+		app.renderForecastTable(tt.forecast)
+		if got := out.String(); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"Overcast", 8, "Overcast"},
+		{"Overcast", 5, "Over…"},
+		{"Überfrierend", 4, "Übe…"},
+		{"Fog", 0, "Fog"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.s, tt.width); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
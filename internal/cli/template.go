@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// formatTemplate is the output format selected by --template and
+// --template-file.
+const formatTemplate = "template"
+
+// templateFuncs are the helpers available to --template, on top of the
+// text/template builtins.
+var templateFuncs = template.FuncMap{
+	"windDir":    weathercli.WindDirection,
+	"condition":  weathercli.GetCondition,
+	"round":      roundTo,
+	"formatTime": formatTimeIn,
+	"convert":    convertUnit,
+	"json":       toJSON,
+}
+
+// parseTemplate returns the template given by --template or
+// --template-file, or nil if neither is set.
+func parseTemplate(g GlobalOptions) (*template.Template, error) {
+	text := g.Template
+	switch {
+	case g.Template != "" && g.TemplateFile != "":
+		return nil, fmt.Errorf("--template and --template-file cannot be combined")
+	case g.TemplateFile != "":
+		data, err := os.ReadFile(g.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	case g.Template == "":
+		return nil, nil
+	}
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("--template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate executes the template against v, the value --format json
+// would encode, and ends the output with a newline if the template does
+// not.
+func (a *App) renderTemplate(v any) error {
+	var b strings.Builder
+	if err := a.template.Execute(&b, v); err != nil {
+		return fmt.Errorf("--template: %w", err)
+	}
+	out := b.String()
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err := fmt.Fprint(a.out, out)
+	return err
+}
+
+// roundTo rounds a number to places decimal places (default 0).
+func roundTo(v any, places ...int) (float64, error) {
+	var f float64
+	switch v := v.(type) {
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	default:
+		return 0, fmt.Errorf("round: %v is not a number", v)
+	}
+	p := math.Pow(10, float64(firstOr(places, 0)))
+	return math.Round(f*p) / p, nil
+}
+
+// formatTimeIn formats t with a Go layout such as "15:04" or "Mon Jan 2".
+// Times are already in the location's timezone; a timezone name (e.g.
+// .Location.Timezone or "UTC") converts first.
+func formatTimeIn(layout string, t time.Time, timezone ...string) (string, error) {
+	if tz := firstOr(timezone, ""); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return "", err
+		}
+		t = t.In(loc)
+	}
+	return t.Format(layout), nil
+}
+
+// convertUnit converts v between units of the same kind: temperature (c,
+// f), wind speed (kmh, ms, mph, kn) or precipitation (mm, inch). Units
+// values such as .Units.Temperature work as from and to.
+func convertUnit(v float64, from, to any) (float64, error) {
+	f, t := unitName(from), unitName(to)
+	switch {
+	case f == t:
+		return v, nil
+	case f == "c" && t == "f":
+		return v*9/5 + 32, nil
+	case f == "f" && t == "c":
+		return (v - 32) * 5 / 9, nil
+	}
+	factors := map[string]float64{
+		"kmh": 1, "ms": 3.6, "mph": 1.609344, "kn": 1.852, // in km/h
+		"mm": 1, "inch": 25.4, // in mm
+	}
+	ff, ok1 := factors[f]
+	tf, ok2 := factors[t]
+	speed := func(u string) bool { return u != "mm" && u != "inch" }
+	if !ok1 || !ok2 || speed(f) != speed(t) {
+		return 0, fmt.Errorf("convert: cannot convert %s to %s", f, t)
+	}
+	return v * ff / tf, nil
+}
+
+// unitName normalizes a unit given as a flag value (c, mph) or an
+// Open-Meteo name (celsius, fahrenheit).
+func unitName(u any) string {
+	s := strings.ToLower(fmt.Sprint(u))
+	switch s {
+	case "celsius", "°c":
+		return "c"
+	case "fahrenheit", "°f":
+		return "f"
+	case "in":
+		return "inch"
+	}
+	return s
+}
+
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func firstOr[T any](values []T, fallback T) T {
+	if len(values) > 0 {
+		return values[0]
+	}
+	return fallback
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	w := &weathercli.CurrentWeather{
+		Location:      weathercli.Location{Name: "Berlin", Timezone: "Europe/Berlin"},
+		Units:         weathercli.MetricUnits(),
+		Time:          time.Date(2024, 1, 12, 14, 0, 0, 0, time.FixedZone("CET", 3600)),
+		Temperature:   3.46,
+		WindSpeed:     18,
+		WindDirection: 250,
+		WeatherCode:   61,
+		Condition:     "Slight rain",
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{.Location.Name}}: {{.Temperature}}°`, "Berlin: 3.46°\n"},
+		{"{{.Location.Name}}\n", "Berlin\n"},
+		{`{{round .Temperature}} {{round .Temperature 1}} {{round .WindDirection}}`, "3 3.5 250\n"},
+		{`{{windDir .WindDirection}} {{condition .WeatherCode}}`, "WSW Slight rain\n"},
+		{`{{formatTime "15:04" .Time}} {{formatTime "15:04 MST" .Time "UTC"}}`, "14:00 13:00 UTC\n"},
+		{`{{convert .Temperature .Units.Temperature "f" | round}} {{round (convert .WindSpeed "kmh" "ms") 1}}`, "38 5\n"},
+		{`{{json .Location}}`, `{"name":"Berlin","latitude":0,"longitude":0,"timezone":"Europe/Berlin"}` + "\n"},
+		{`{{if .Stale}}stale{{end}}`, ""},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := parseTemplate(GlobalOptions{Template: tt.template})
+		if err != nil {
+			t.Errorf("%s: %v", tt.template, err)
+			continue
+		}
+		var out strings.Builder
+		app := &App{out: &out, template: tmpl}
+		if err := app.renderTemplate(w); err != nil {
+			t.Errorf("%s: %v", tt.template, err)
+			continue
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "out.tmpl")
+	if err := os.WriteFile(file, []byte("{{.Temperature}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parseTests := []struct {
+		opts    GlobalOptions
+		wantErr string
+	}{
+		{GlobalOptions{Template: "x", TemplateFile: file}, "--template and --template-file cannot be combined"},
+		{GlobalOptions{TemplateFile: filepath.Join(t.TempDir(), "missing")}, "no such file"},
+		{GlobalOptions{Template: "{{.Temperature"}, "--template: template: output:1: unclosed action"},
+		{GlobalOptions{Template: "{{nope .Temperature}}"}, `--template: template: output:1: function "nope" not defined`},
+	}
+	for _, tt := range parseTests {
+		_, err := parseTemplate(tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseTemplate(%+v) error = %v, want %q", tt.opts, err, tt.wantErr)
+		}
+	}
+
+	if tmpl, err := parseTemplate(GlobalOptions{}); tmpl != nil || err != nil {
+		t.Errorf("parseTemplate with no template = %v, %v, want nil, nil", tmpl, err)
+	}
+	if tmpl, err := parseTemplate(GlobalOptions{TemplateFile: file}); tmpl == nil || err != nil {
+		t.Errorf("parseTemplate(--template-file) = %v, %v", tmpl, err)
+	}
+
+	execTests := []struct {
+		template string
+		wantErr  string
+	}{
+		{"{{.Nope}}", "--template: template: output:1:2: executing \"output\" at <.Nope>: can't evaluate field Nope"},
+		{"{{round .Condition}}", "round: Slight rain is not a number"},
+		{`{{convert .Temperature "c" "mph"}}`, "convert: cannot convert c to mph"},
+		{`{{convert .WindSpeed "kmh" "mm"}}`, "convert: cannot convert kmh to mm"},
+		{`{{formatTime "15:04" .Time "Nowhere/City"}}`, "unknown time zone Nowhere/City"},
+	}
+	w := &weathercli.CurrentWeather{Condition: "Slight rain"}
+	for _, tt := range execTests {
+		tmpl, err := parseTemplate(GlobalOptions{Template: tt.template})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.template, err)
+		}
+		var out strings.Builder
+		app := &App{out: &out, template: tmpl}
+		err = app.renderTemplate(w)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.template, err, tt.wantErr)
+		}
+		if out.Len() > 0 {
+			t.Errorf("%s: wrote %q despite the error", tt.template, out.String())
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package cli
+
+import "os"
+
+// terminalWidth is unknown on this platform.
+func terminalWidth(*os.File) int {
+	return 0
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pjtf93/weathercli"
+)
+
+// minWatchInterval keeps --watch from hammering the API. Open-Meteo updates
+// current conditions every 15 minutes, so intervals of several minutes are
+// usually enough; shorter ones mostly fetch unchanged data.
+const minWatchInterval = 10 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// checkWatch validates --watch for the number of locations given.
+func checkWatch(interval time.Duration, locations int) error {
+	switch {
+	case interval == 0:
+		return nil
+	case interval < minWatchInterval:
+		return usagef("--watch interval must be at least %s", minWatchInterval)
+	case locations > 1:
+		return usagef("--watch takes a single location")
+	}
+	return nil
+}
+
+// watch runs CurrentCmd --watch until interrupted.
+func (c *CurrentCmd) watch(ctx context.Context, app *App, loc weathercli.Location) error {
+	first := true
+	for u := range app.client.Watch(ctx, loc, c.Watch) {
+		if err := app.renderWatch(u.Time, u.Err, c.Watch, first, func() error {
+			return app.RenderCurrentWeather(u.Weather)
+		}); err != nil {
+			return err
+		}
+		first = first && u.Err != nil
+	}
+	return nil
+}
+
+// watch runs ForecastCmd --watch until interrupted.
+func (c *ForecastCmd) watch(ctx context.Context, app *App, loc weathercli.Location, days int) error {
+	first := true
+	for u := range app.client.WatchForecast(ctx, loc, days, c.Hourly, c.Watch) {
+		if err := app.renderWatch(u.Time, u.Err, c.Watch, first, func() error {
+			if c.Hourly && len(u.Forecast.Hourly) > c.Hours {
+				u.Forecast.Hourly = u.Forecast.Hourly[:c.Hours]
+			}
+			return app.RenderForecast(u.Forecast)
+		}); err != nil {
+			return err
+		}
+		first = first && u.Err != nil
+	}
+	return nil
+}
+
+// renderWatch outputs one refresh. Structured output is written as is
+// (one line per refresh with --json); in a terminal the screen is redrawn
+// in place; otherwise refreshes are appended. Failed refreshes are
+// reported on stderr and the watch goes on.
+func (a *App) renderWatch(t time.Time, err error, interval time.Duration, first bool, render func() error) error {
+	if err != nil {
+		if jsonErrors(a.format) {
+			writeErrorJSON(a.err, err)
+		} else {
+			fmt.Fprintf(a.err, "%s %s: %v\n", a.color.Red("Error:"), t.Format("15:04:05"), err)
+		}
+		return nil
+	}
+
+	switch {
+	case a.structured():
+		return render()
+	case a.tty:
+		fmt.Fprint(a.out, clearScreen)
+	case !first:
+		fmt.Fprintln(a.out)
+	}
+	if err := render(); err != nil {
+		return err
+	}
+	if a.tty {
+		fmt.Fprintln(a.out)
+		fmt.Fprintln(a.out, a.color.Cyan(fmt.Sprintf("Updated %s, refreshing every %s. Press Ctrl-C to stop.", t.Format("15:04:05"), interval)))
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// yamlMap is a JSON object with its key order kept.
+type yamlMap []yamlField
+
+type yamlField struct {
+	key   string
+	value any
+}
+
+// writeYAML writes v as YAML. It goes through v's JSON encoding, so field
+// names, order and omitempty match --format json.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := decodeOrdered(dec)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	switch node := node.(type) {
+	case yamlMap:
+		if len(node) == 0 {
+			b.WriteString("{}\n")
+		}
+		writeYAMLMap(&b, node, 0, false)
+	case []any:
+		if len(node) == 0 {
+			b.WriteString("[]\n")
+		}
+		writeYAMLList(&b, node, 0)
+	default:
+		b.WriteString(yamlScalar(node) + "\n")
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// decodeOrdered decodes the next JSON value into yamlMap, []any, string,
+// json.Number, bool or nil.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := yamlMap{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, yamlField{key: key.(string), value: value})
+		}
+		_, err := dec.Token()
+		return m, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// writeYAMLMap writes m's fields at indent. With inline, the first field
+// follows a "- " already written.
+func writeYAMLMap(b *strings.Builder, m yamlMap, indent int, inline bool) {
+	for i, f := range m {
+		if i > 0 || !inline {
+			b.WriteString(strings.Repeat(" ", indent))
+		}
+		b.WriteString(yamlKey(f.key) + ":")
+		writeYAMLValue(b, f.value, indent+2)
+	}
+}
+
+func writeYAMLList(b *strings.Builder, list []any, indent int) {
+	for _, item := range list {
+		b.WriteString(strings.Repeat(" ", indent) + "-")
+		if m, ok := item.(yamlMap); ok && len(m) > 0 {
+			b.WriteString(" ")
+			writeYAMLMap(b, m, indent+2, true)
+			continue
+		}
+		writeYAMLValue(b, item, indent+2)
+	}
+}
+
+// writeYAMLValue writes the value after a "key:" or "-".
+func writeYAMLValue(b *strings.Builder, v any, indent int) {
+	switch v := v.(type) {
+	case yamlMap:
+		if len(v) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAMLMap(b, v, indent, false)
+	case []any:
+		if len(v) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAMLList(b, v, indent)
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+func yamlScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	}
+	return ""
+}
+
+func yamlKey(s string) string {
+	return yamlString(s)
+}
+
+// yamlSexagesimal matches YAML 1.1 base-60 numbers such as 10:30, which
+// would read back as integers.
+var yamlSexagesimal = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+(\.[0-9_]*)?$`)
+
+// yamlString quotes s unless it reads back as the same plain string.
+func yamlString(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, "\n\t\"'\\`{}[],&*#?|<>=!%@") ||
+		strings.Contains(s, ": ") || strings.HasSuffix(s, ":") || strings.HasPrefix(s, "- ") || s == "-" ||
+		strings.IndexFunc(s, unicode.IsControl) >= 0 || yamlSexagesimal.MatchString(s) {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil || strings.HasPrefix(s, "0") || strings.HasPrefix(s, "+") || strings.HasPrefix(s, ".") {
+		return strconv.Quote(s)
+	}
+	return s
+}
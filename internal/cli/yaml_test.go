@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestYAMLString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Berlin", "Berlin"},
+		{"New York", "New York"},
+		{"Europe/Berlin", "Europe/Berlin"},
+		{"São Paulo", "São Paulo"},
+		{"", `""`},
+		{" padded", `" padded"`},
+		{"trailing ", `"trailing "`},
+		{"two\nlines", `"two\nlines"`},
+		{"tab\there", `"tab\there"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"it's", `"it's"`},
+		{`back\slash`, `"back\\slash"`},
+		{"key: value", `"key: value"`},
+		{"ends:", `"ends:"`},
+		{"12:30", `"12:30"`},
+		{"-1:20:05.5", `"-1:20:05.5"`},
+		{"12:75", "12:75"},
+		{"- item", `"- item"`},
+		{"-", `"-"`},
+		{"#comment", `"#comment"`},
+		{"a, b", `"a, b"`},
+		{"{x}", `"{x}"`},
+		{"[x]", `"[x]"`},
+		{"&anchor", `"&anchor"`},
+		{"*alias", `"*alias"`},
+		{"@home", `"@home"`},
+		{"true", `"true"`},
+		{"No", `"No"`},
+		{"y", `"y"`},
+		{"null", `"null"`},
+		{"~", `"~"`},
+		{"42", `"42"`},
+		{"-3.5", `"-3.5"`},
+		{"1e3", `"1e3"`},
+		{"0755", `"0755"`},
+		{"+1", `"+1"`},
+		{".5", `".5"`},
+		{"2024-01-12", "2024-01-12"},
+		{"\x00", `"\x00"`},
+	}
+	for _, tt := range tests {
+		if got := yamlString(tt.in); got != tt.want {
+			t.Errorf("yamlString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	type point struct {
+		Time  string   `json:"time"`
+		Value *float64 `json:"value"`
+	}
+	type doc struct {
+		Name    string            `json:"name"`
+		Note    string            `json:"note,omitempty"`
+		Count   int               `json:"count"`
+		Ratio   float64           `json:"ratio"`
+		OK      bool              `json:"ok"`
+		Tags    []string          `json:"tags"`
+		Empty   []string          `json:"empty"`
+		Meta    map[string]string `json:"meta"`
+		None    map[string]string `json:"none"`
+		Points  []point           `json:"points"`
+		Nested  [][]int           `json:"nested"`
+		Special string            `json:"key: odd"`
+	}
+	v := 1.5
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{
+			name: "object",
+			v: doc{
+				Name:    "Berlin",
+				Count:   3,
+				Ratio:   0.25,
+				OK:      true,
+				Tags:    []string{"a", "yes"},
+				Empty:   []string{},
+				Meta:    map[string]string{"b": "2", "a": "x: y"},
+				Points:  []point{{"09:00", &v}, {"10:00", nil}},
+				Nested:  [][]int{{1, 2}, {}},
+				Special: "",
+			},
+			want: `name: Berlin
+count: 3
+ratio: 0.25
+ok: true
+tags:
+  - a
+  - "yes"
+empty: []
+meta:
+  a: "x: y"
+  b: "2"
+none: null
+points:
+  - time: "09:00"
+    value: 1.5
+  - time: "10:00"
+    value: null
+nested:
+  -
+    - 1
+    - 2
+  - []
+"key: odd": ""
+`,
+		},
+		{name: "empty object", v: struct{}{}, want: "{}\n"},
+		{name: "empty list", v: []int{}, want: "[]\n"},
+		{name: "list of objects", v: []point{{Time: "x"}}, want: "- time: x\n  value: null\n"},
+		{name: "scalar", v: "on", want: "\"on\"\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := writeYAML(&b, tt.v); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package weathercli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Variables requested from the marine API.
+const (
+	marineFields      = "wave_height,wave_direction,wave_period,swell_wave_height,swell_wave_direction,swell_wave_period,sea_surface_temperature,sea_level_height_msl"
+	marineDailyFields = "wave_height_max,wave_direction_dominant,wave_period_max,swell_wave_height_max,swell_wave_direction_dominant,swell_wave_period_max"
+)
+
+// ErrNoMarineData is returned by Marine when the API has no wave data for
+// the location, typically because it is inland.
+var ErrNoMarineData = errors.New("no marine data for this location (inland or outside model coverage)")
+
+// Marine fetches current sea conditions plus a daily or hourly marine
+// forecast by coordinates. Wave heights are in meters, or feet when the
+// client uses inch precipitation units.
+func (c *Client) Marine(ctx context.Context, lat, lon float64, days int, hourly bool, loc *Location) (*MarineForecast, error) {
+	u, err := url.Parse(c.marineBaseURL + "/marine")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("latitude", fmt.Sprintf("%.4f", lat))
+	q.Set("longitude", fmt.Sprintf("%.4f", lon))
+	q.Set("current", marineFields)
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", fmt.Sprintf("%d", days))
+	q.Set("temperature_unit", string(c.units.Temperature))
+	q.Set("length_unit", c.units.lengthUnit())
+
+	if hourly {
+		q.Set("hourly", marineFields)
+	} else {
+		q.Set("daily", marineDailyFields)
+	}
+
+	u.RawQuery = q.Encode()
+
+	var result struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Timezone  string  `json:"timezone"`
+		Current   struct {
+			Time string `json:"time"`
+			marineValues
+		} `json:"current"`
+		Hourly struct {
+			Time               []string   `json:"time"`
+			WaveHeight         []*float64 `json:"wave_height"`
+			WaveDirection      []*float64 `json:"wave_direction"`
+			WavePeriod         []*float64 `json:"wave_period"`
+			SwellWaveHeight    []*float64 `json:"swell_wave_height"`
+			SwellWaveDirection []*float64 `json:"swell_wave_direction"`
+			SwellWavePeriod    []*float64 `json:"swell_wave_period"`
+			SeaSurfaceTemp     []*float64 `json:"sea_surface_temperature"`
+			SeaLevelHeight     []*float64 `json:"sea_level_height_msl"`
+		} `json:"hourly"`
+		Daily struct {
+			Time               []string   `json:"time"`
+			WaveHeightMax      []*float64 `json:"wave_height_max"`
+			WaveDirection      []*float64 `json:"wave_direction_dominant"`
+			WavePeriodMax      []*float64 `json:"wave_period_max"`
+			SwellWaveHeightMax []*float64 `json:"swell_wave_height_max"`
+			SwellWaveDirection []*float64 `json:"swell_wave_direction_dominant"`
+			SwellWavePeriodMax []*float64 `json:"swell_wave_period_max"`
+		} `json:"daily"`
+	}
+
+	meta, err := c.getJSON(ctx, u, "marine", &result)
+	if err != nil {
+		return nil, err
+	}
+
+	// The API answers inland requests with nulls rather than an error.
+	if result.Current.WaveHeight == nil && allNull(result.Hourly.WaveHeight) && allNull(result.Daily.WaveHeightMax) {
+		return nil, ErrNoMarineData
+	}
+
+	tz := loadTimezone(result.Timezone)
+
+	t, err := time.ParseInLocation(timeLayout, result.Current.Time, tz)
+	if err != nil {
+		return nil, &DecodeError{Endpoint: "marine", Err: fmt.Errorf("failed to parse time %q: %w", result.Current.Time, err)}
+	}
+
+	marine := &MarineForecast{
+		SchemaVersion: SchemaVersion,
+		Units:         c.units,
+		Current:       result.Current.conditions(t),
+	}
+	marine.Stale, marine.CachedAt = meta.staleSince()
+
+	if loc != nil {
+		marine.Location = *loc
+		marine.Location.Timezone = result.Timezone
+	} else {
+		marine.Location = Location{
+			Latitude:  result.Latitude,
+			Longitude: result.Longitude,
+			Timezone:  result.Timezone,
+		}
+	}
+
+	h := result.Hourly
+	for i := range h.Time {
+		t, err := time.ParseInLocation(timeLayout, h.Time[i], tz)
+		if err != nil {
+			return nil, &DecodeError{Endpoint: "marine", Err: fmt.Errorf("failed to parse hourly time %q: %w", h.Time[i], err)}
+		}
+		v := marineValues{
+			WaveHeight:         at(h.WaveHeight, i),
+			WaveDirection:      at(h.WaveDirection, i),
+			WavePeriod:         at(h.WavePeriod, i),
+			SwellWaveHeight:    at(h.SwellWaveHeight, i),
+			SwellWaveDirection: at(h.SwellWaveDirection, i),
+			SwellWavePeriod:    at(h.SwellWavePeriod, i),
+			SeaSurfaceTemp:     at(h.SeaSurfaceTemp, i),
+			SeaLevelHeight:     at(h.SeaLevelHeight, i),
+		}
+		marine.Hourly = append(marine.Hourly, v.conditions(t))
+	}
+
+	d := result.Daily
+	for i := range d.Time {
+		date, err := time.Parse(dateLayout, d.Time[i])
+		if err != nil {
+			return nil, &DecodeError{Endpoint: "marine", Err: fmt.Errorf("failed to parse date %q: %w", d.Time[i], err)}
+		}
+		marine.Daily = append(marine.Daily, MarineDaily{
+			Date:               date,
+			WaveHeightMax:      at(d.WaveHeightMax, i),
+			WaveDirection:      direction(at(d.WaveDirection, i)),
+			WavePeriodMax:      at(d.WavePeriodMax, i),
+			SwellWaveHeightMax: at(d.SwellWaveHeightMax, i),
+			SwellWaveDirection: direction(at(d.SwellWaveDirection, i)),
+			SwellWavePeriodMax: at(d.SwellWavePeriodMax, i),
+		})
+	}
+
+	return marine, nil
+}
+
+// marineValues holds one time step of the raw marine response.
+type marineValues struct {
+	WaveHeight         *float64 `json:"wave_height"`
+	WaveDirection      *float64 `json:"wave_direction"`
+	WavePeriod         *float64 `json:"wave_period"`
+	SwellWaveHeight    *float64 `json:"swell_wave_height"`
+	SwellWaveDirection *float64 `json:"swell_wave_direction"`
+	SwellWavePeriod    *float64 `json:"swell_wave_period"`
+	SeaSurfaceTemp     *float64 `json:"sea_surface_temperature"`
+	SeaLevelHeight     *float64 `json:"sea_level_height_msl"`
+}
+
+func (v marineValues) conditions(t time.Time) MarineConditions {
+	return MarineConditions{
+		Time:               t,
+		WaveHeight:         v.WaveHeight,
+		WaveDirection:      direction(v.WaveDirection),
+		WavePeriod:         v.WavePeriod,
+		SwellWaveHeight:    v.SwellWaveHeight,
+		SwellWaveDirection: direction(v.SwellWaveDirection),
+		SwellWavePeriod:    v.SwellWavePeriod,
+		SeaSurfaceTemp:     v.SeaSurfaceTemp,
+		SeaLevelHeight:     v.SeaLevelHeight,
+	}
+}
+
+// direction converts a direction in degrees to whole degrees, keeping
+// nulls.
+func direction(p *float64) *int {
+	if p == nil {
+		return nil
+	}
+	d := int(*p)
+	return &d
+}
+
+// allNull reports whether every value in s is null.
+func allNull(s []*float64) bool {
+	for _, v := range s {
+		if v != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package weathercli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMarine(t *testing.T) {
+	var lengthUnit string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lengthUnit = r.URL.Query().Get("length_unit")
+		_, _ = w.Write([]byte(`{"latitude":43.48,"longitude":-1.56,"timezone":"Europe/Paris",
+			"current":{"time":"2024-06-01T10:00","wave_height":1.4,"wave_direction":290,"wave_period":9.1,
+				"swell_wave_height":1.1,"swell_wave_direction":285,"swell_wave_period":12.3,
+				"sea_surface_temperature":17.2,"sea_level_height_msl":null},
+			"daily":{"time":["2024-06-01"],"wave_height_max":[1.8],"wave_direction_dominant":[290],"wave_period_max":[10.2],
+				"swell_wave_height_max":[1.3],"swell_wave_direction_dominant":[285],"swell_wave_period_max":[12.8]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{MarineBaseURL: srv.URL})
+	marine, err := client.Marine(context.Background(), 43.48, -1.56, 1, false, nil)
+	if err != nil {
+		t.Fatalf("Marine failed: %v", err)
+	}
+
+	if lengthUnit != "metric" {
+		t.Errorf("length_unit = %q, want metric", lengthUnit)
+	}
+	cur := marine.Current
+	if *cur.WaveHeight != 1.4 || *cur.SwellWavePeriod != 12.3 || *cur.WaveDirection != 290 {
+		t.Errorf("Current = %+v", cur)
+	}
+	if cur.SeaSurfaceTemp == nil || *cur.SeaSurfaceTemp != 17.2 {
+		t.Errorf("SeaSurfaceTemp = %v, want 17.2", cur.SeaSurfaceTemp)
+	}
+	if cur.SeaLevelHeight != nil {
+		t.Errorf("SeaLevelHeight = %v, want nil for null", *cur.SeaLevelHeight)
+	}
+	if len(marine.Daily) != 1 || *marine.Daily[0].WaveHeightMax != 1.8 {
+		t.Errorf("Daily = %+v", marine.Daily)
+	}
+}
+
+func TestMarineInland(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"latitude":48.14,"longitude":11.58,"timezone":"Europe/Berlin",
+			"current":{"time":"2024-06-01T10:00","wave_height":null,"sea_surface_temperature":null},
+			"hourly":{"time":["2024-06-01T00:00","2024-06-01T01:00"],"wave_height":[null,null]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{MarineBaseURL: srv.URL})
+	_, err := client.Marine(context.Background(), 48.14, 11.58, 1, true, nil)
+	if !errors.Is(err, ErrNoMarineData) {
+		t.Errorf("Marine inland error = %v, want ErrNoMarineData", err)
+	}
+}
+
+func TestMarinePartialNulls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"latitude":43.48,"longitude":-1.56,"timezone":"Europe/Paris",
+			"current":{"time":"2024-06-01T10:00","wave_height":1.4,"wave_direction":null,"wave_period":null},
+			"hourly":{"time":["2024-06-01T00:00","2024-06-01T01:00"],"wave_height":[0.8,null],"wave_direction":[270,null]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{MarineBaseURL: srv.URL})
+	marine, err := client.Marine(context.Background(), 43.48, -1.56, 1, true, nil)
+	if err != nil {
+		t.Fatalf("Marine failed: %v", err)
+	}
+
+	if cur := marine.Current; cur.WaveHeight == nil || cur.WaveDirection != nil || cur.WavePeriod != nil {
+		t.Errorf("Current = %+v, want only WaveHeight set", cur)
+	}
+	if len(marine.Hourly) != 2 {
+		t.Fatalf("got %d hours, want 2", len(marine.Hourly))
+	}
+	if h := marine.Hourly[0]; *h.WaveHeight != 0.8 || *h.WaveDirection != 270 || h.SwellWaveHeight != nil {
+		t.Errorf("Hourly[0] = %+v", h)
+	}
+	if h := marine.Hourly[1]; h.WaveHeight != nil || h.WaveDirection != nil {
+		t.Errorf("Hourly[1] = %+v, want null waves kept as nil", h)
+	}
+}
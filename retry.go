@@ -0,0 +1,85 @@
+package weathercli
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// RetryPolicy controls the backoff between retries of failed requests.
+// Network errors, 429 and 5xx responses are retried up to
+// Options.MaxRetries times.
+type RetryPolicy struct {
+	BaseDelay time.Duration // First backoff, doubled per attempt; defaults to 500ms
+	MaxDelay  time.Duration // Backoff and Retry-After cap; defaults to 10s
+
+	// OnRetry, if set, is called before sleeping for each retry. attempt
+	// counts retries from 1.
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultRetryBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultRetryMaxDelay
+	}
+	return p
+}
+
+// backoff returns the delay before retry number attempt (from 1): an
+// exponential step with jitter, between half and all of the step.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	step := p.BaseDelay << (attempt - 1)
+	if step > p.MaxDelay || step <= 0 {
+		step = p.MaxDelay
+	}
+	half := step / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1)) //nolint:gosec // jitter needs no crypto randomness
+}
+
+// retryableStatus reports whether an HTTP status is worth retrying.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date. It returns 0 when the header is absent or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done, reporting whether the full delay
+// elapsed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
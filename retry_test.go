@@ -0,0 +1,159 @@
+package weathercli
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransientFailures(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(testCurrentResponse))
+	}))
+	defer srv.Close()
+
+	var retries []int
+	client := NewClient(Options{
+		BaseURL:    srv.URL,
+		MaxRetries: 2,
+		RetryPolicy: RetryPolicy{
+			BaseDelay: time.Millisecond,
+			OnRetry:   func(attempt int, _ time.Duration, _ error) { retries = append(retries, attempt) },
+		},
+	})
+
+	if _, err := client.CurrentByCoords(context.Background(), 52.52, 13.41, nil); err != nil {
+		t.Fatalf("CurrentByCoords failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+	if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("OnRetry attempts = %v, want [1 2]", retries)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   int32
+	}{
+		{"client errors are not retried", http.StatusBadRequest, 1},
+		{"retries are bounded", http.StatusBadGateway, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			client := NewClient(Options{BaseURL: srv.URL, MaxRetries: 2, RetryPolicy: RetryPolicy{BaseDelay: time.Millisecond}})
+			if _, err := client.CurrentByCoords(context.Background(), 1, 2, nil); err == nil {
+				t.Fatal("Expected error")
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.want {
+				t.Errorf("requests = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterRespectsDeadline(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient(Options{BaseURL: srv.URL, MaxRetries: 3})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.CurrentByCoords(ctx, 1, 2, nil); err == nil {
+		t.Fatal("Expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("gave up after %s, want immediately when Retry-After exceeds the deadline", elapsed)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestRetryAfterBeyondMaxDelay(t *testing.T) {
+	for _, header := range []string{"3600", time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat)} {
+		var requests int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.Header().Set("Retry-After", header)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+
+		// No deadline, as in the CLI.
+		client := NewClient(Options{BaseURL: srv.URL, MaxRetries: 3})
+		start := time.Now()
+		_, err := client.CurrentByCoords(context.Background(), 1, 2, nil)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("Retry-After %s: error = %v, want the 429 APIError", header, err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("Retry-After %s: gave up after %s, want immediately", header, elapsed)
+		}
+		if got := atomic.LoadInt32(&requests); got != 1 {
+			t.Errorf("Retry-After %s: requests = %d, want 1", header, got)
+		}
+		srv.Close()
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 12, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{"Fri, 12 Jan 2024 10:00:30 GMT", 30 * time.Second},
+		{"Fri, 12 Jan 2024 09:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 1; attempt <= 10; attempt++ {
+		step := p.BaseDelay << (attempt - 1)
+		if step > p.MaxDelay {
+			step = p.MaxDelay
+		}
+		got := p.backoff(attempt)
+		if got < step/2 || got > step {
+			t.Errorf("backoff(%d) = %s, want within [%s, %s]", attempt, got, step/2, step)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package weathercli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// DefaultConfigDir returns $XDG_CONFIG_HOME/weathercli, falling back to the
+// platform user config directory.
+func DefaultConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "weathercli"), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "weathercli"), nil
+}
+
+// SavedLocations maps names such as "home" or "office-berlin" to resolved
+// locations.
+type SavedLocations map[string]Location
+
+var savedNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateSavedName checks that name can be used as a saved location name:
+// letters, digits, '.', '_' and '-', not starting with punctuation.
+func ValidateSavedName(name string) error {
+	if !savedNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid location name %q (use letters, digits, '.', '_' and '-')", name)
+	}
+	return nil
+}
+
+// LoadSavedLocations reads saved locations from path. A missing file is an
+// empty set.
+func LoadSavedLocations(path string) (SavedLocations, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return SavedLocations{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	saved := SavedLocations{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid saved locations file %s: %w", path, err)
+	}
+	return saved, nil
+}
+
+// Save writes the locations to path atomically, creating its directory.
+func (s SavedLocations) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// Names returns the saved names, sorted.
+func (s SavedLocations) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
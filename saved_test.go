@@ -0,0 +1,65 @@
+package weathercli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSavedLocationsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "locations.json")
+
+	saved, err := LoadSavedLocations(path)
+	if err != nil {
+		t.Fatalf("LoadSavedLocations on missing file: %v", err)
+	}
+	if len(saved) != 0 {
+		t.Fatalf("missing file = %v, want empty", saved)
+	}
+
+	saved["office-berlin"] = Location{Name: "Berlin", Latitude: 52.52, Longitude: 13.41, Timezone: "Europe/Berlin"}
+	saved["home"] = Location{Name: "home", Latitude: 45.52, Longitude: -122.68, Timezone: "America/Los_Angeles"}
+	if err := saved.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadSavedLocations(path)
+	if err != nil {
+		t.Fatalf("LoadSavedLocations failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("loaded = %+v, want %+v", loaded, saved)
+	}
+	if names := loaded.Names(); !reflect.DeepEqual(names, []string{"home", "office-berlin"}) {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+func TestValidateSavedName(t *testing.T) {
+	valid := []string{"home", "office-berlin", "cabin_2", "Work.NYC"}
+	for _, name := range valid {
+		if err := ValidateSavedName(name); err != nil {
+			t.Errorf("ValidateSavedName(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := []string{"", "-home", "@home", "my home", "a/b", "id:1"}
+	for _, name := range invalid {
+		if err := ValidateSavedName(name); err == nil {
+			t.Errorf("ValidateSavedName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestDefaultConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	got, err := DefaultConfigDir()
+	if err != nil {
+		t.Fatalf("DefaultConfigDir failed: %v", err)
+	}
+	if want := filepath.Join(dir, "weathercli"); got != want {
+		t.Errorf("DefaultConfigDir() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,261 @@
+package weathercli
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON output format, reported as
+// schema_version. It changes when a field is removed, renamed or changes
+// type; added fields do not change it.
+const SchemaVersion = "1"
+
+// Output schema names for OutputSchema.
+const (
+	SchemaCurrent  = "current"
+	SchemaForecast = "forecast"
+	SchemaSearch   = "search"
+	SchemaError    = "error"
+)
+
+// SchemaNames lists the schemas OutputSchema generates.
+func SchemaNames() []string {
+	return []string{SchemaCurrent, SchemaForecast, SchemaSearch, SchemaError}
+}
+
+// OutputSchema returns a JSON Schema (draft 2020-12) for a JSON output:
+// current (CurrentWeather), forecast (Forecast, also used by history),
+// search ([]Location, a bare array without schema_version) or error
+// (ErrorResponse). It is generated from the Go types, with descriptions,
+// units and enums such as the WMO weather codes.
+func OutputSchema(name string) (map[string]any, error) {
+	var v any
+	title := ""
+	switch name {
+	case SchemaCurrent:
+		v, title = CurrentWeather{}, "Current weather"
+	case SchemaForecast:
+		v, title = Forecast{}, "Daily or hourly forecast"
+	case SchemaSearch:
+		v, title = []Location{}, "Location search results (a bare array, without schema_version)"
+	case SchemaError:
+		v, title = ErrorResponse{}, "Error"
+	default:
+		return nil, fmt.Errorf("unknown schema %q (want one of %s)", name, strings.Join(SchemaNames(), ", "))
+	}
+
+	g := schemaGenerator{defs: map[string]any{}}
+	var schema map[string]any
+	if t := reflect.TypeOf(v); t.Kind() == reflect.Struct {
+		schema = g.object(t) // the root type is inlined
+	} else {
+		schema = g.schema(t, "")
+	}
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "weathercli " + name + ": " + title
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	return schema, nil
+}
+
+type schemaGenerator struct {
+	defs map[string]any // named struct types, referenced as #/$defs/Name
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema returns the schema for t. key is the "Type.field" the value is
+// for, used to look up descriptions and enums.
+func (g *schemaGenerator) schema(t reflect.Type, key string) map[string]any {
+	s := map[string]any{}
+	switch {
+	case t == timeType:
+		s["type"] = "string"
+		s["format"] = "date-time"
+	case t.Kind() == reflect.Pointer:
+		return g.schema(t.Elem(), key)
+	case t.Kind() == reflect.Slice:
+		s["type"] = "array"
+		s["items"] = g.schema(t.Elem(), "")
+	case t.Kind() == reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // placeholder for recursive types
+			g.defs[t.Name()] = g.object(t)
+		}
+		s["$ref"] = "#/$defs/" + t.Name()
+	case t.Kind() == reflect.String:
+		s["type"] = "string"
+	case t.Kind() == reflect.Bool:
+		s["type"] = "boolean"
+	case t.Kind() == reflect.Int || t.Kind() == reflect.Int64:
+		s["type"] = "integer"
+	case t.Kind() == reflect.Float64:
+		s["type"] = "number"
+	}
+
+	if enum, ok := schemaEnums[t]; ok {
+		s["enum"] = enum
+	}
+	if enum, ok := schemaFieldEnums[key]; ok {
+		s["enum"] = enum
+	}
+	if desc, ok := schemaDescriptions[key]; ok {
+		s["description"] = desc
+	}
+	return s
+}
+
+// object returns the schema of a struct: its JSON fields as properties,
+// required unless omitempty. Other properties are allowed, since fields
+// may be added without a new SchemaVersion.
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop := g.schema(f.Type, t.Name()+"."+name)
+		if name == "schema_version" {
+			prop["const"] = SchemaVersion
+		}
+		properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	s := map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if desc, ok := schemaDescriptions[t.Name()]; ok {
+		s["description"] = desc
+	}
+	return s
+}
+
+var schemaEnums = map[reflect.Type][]any{
+	reflect.TypeOf(TemperatureUnit("")):   {string(Celsius), string(Fahrenheit)},
+	reflect.TypeOf(WindSpeedUnit("")):     {string(KilometersPerHour), string(MetersPerSecond), string(MilesPerHour), string(Knots)},
+	reflect.TypeOf(PrecipitationUnit("")): {string(Millimeters), string(Inches)},
+}
+
+// schemaFieldEnums are enums of plain fields, keyed "Type.field".
+var schemaFieldEnums = func() map[string][]any {
+	var codes []int
+	for code := range WeatherCode {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	var codeEnum, conditionEnum []any
+	for _, code := range codes {
+		codeEnum = append(codeEnum, code)
+		conditionEnum = append(conditionEnum, WeatherCode[code])
+	}
+	conditionEnum = append(conditionEnum, GetCondition(-1))
+
+	enums := map[string][]any{
+		"Units.system": {UnitSystemMetric, UnitSystemImperial, UnitSystemCustom},
+		"ErrorDetail.code": {
+			ErrorCodeInvalidRequest, ErrorCodeLocationNotFound, ErrorCodeNoMarineData, ErrorCodeNetwork, ErrorCodeOffline,
+			ErrorCodeAPI, ErrorCodeRateLimited, ErrorCodeBadResponse, ErrorCodeInternal,
+		},
+	}
+	for _, typ := range []string{"CurrentWeather", "DailyForecast", "HourlyForecast"} {
+		enums[typ+".weather_code"] = codeEnum
+		enums[typ+".condition"] = conditionEnum
+	}
+	return enums
+}()
+
+// schemaDescriptions describe types and fields, keyed "Type" or
+// "Type.field". Measurements name their unit.
+var schemaDescriptions = func() map[string]string {
+	d := map[string]string{
+		"CurrentWeather": "Current conditions at a location. Measurements are in the units given by units.",
+		"Forecast":       "A daily or hourly forecast (or history). Measurements are in the units given by units.",
+		"DailyForecast":  "One day of a forecast.",
+		"HourlyForecast": "One hour of a forecast.",
+		"Location":       "A geocoded place.",
+		"Units":          "Measurement units of the response.",
+		"ErrorResponse":  "A failed request.",
+		"ErrorDetail":    "What went wrong.",
+
+		"ErrorDetail.code":      "Stable error code; match on this rather than message.",
+		"ErrorDetail.message":   "Human-readable description; may change.",
+		"ErrorDetail.status":    "HTTP status of the error (serve answers with it).",
+		"ErrorDetail.retryable": "Whether retrying later may succeed.",
+
+		"Location.id":           "GeoNames ID; usable as id:N.",
+		"Location.name":         "Place name, or coordinates for coordinate queries.",
+		"Location.latitude":     "Degrees north.",
+		"Location.longitude":    "Degrees east.",
+		"Location.elevation":    "Meters above sea level.",
+		"Location.country":      "Country name.",
+		"Location.country_code": "ISO 3166-1 alpha-2 country code.",
+		"Location.admin1":       "State or province.",
+		"Location.admin2":       "County or district.",
+		"Location.timezone":     "IANA timezone; times are given in it.",
+		"Location.population":   "Population.",
+		"Location.postcodes":    "Postal codes.",
+
+		"Units.system":        "Unit system: metric (°C, km/h, mm), imperial (°F, mph, inch) or custom.",
+		"Units.temperature":   "Unit of temperatures.",
+		"Units.wind_speed":    "Unit of wind speeds.",
+		"Units.precipitation": "Unit of precipitation amounts.",
+
+		"Forecast.daily":  "Daily entries; absent for hourly forecasts.",
+		"Forecast.hourly": "Hourly entries; absent for daily forecasts.",
+
+		"DailyForecast.date":           "Calendar date, at 00:00 UTC.",
+		"DailyForecast.temp_max":       "Maximum temperature (units.temperature).",
+		"DailyForecast.temp_min":       "Minimum temperature (units.temperature).",
+		"DailyForecast.apparent_max":   "Maximum feels-like temperature (units.temperature).",
+		"DailyForecast.apparent_min":   "Minimum feels-like temperature (units.temperature).",
+		"DailyForecast.precipitation":  "Total precipitation (units.precipitation).",
+		"DailyForecast.rain":           "Total rain (units.precipitation).",
+		"DailyForecast.snowfall":       "Total snowfall (cm, or inch with imperial precipitation).",
+		"DailyForecast.wind_speed_max": "Maximum wind speed (units.wind_speed).",
+		"DailyForecast.wind_direction": "Dominant wind direction in degrees (0 = north).",
+		"DailyForecast.uv_index_max":   "Maximum UV index.",
+		"DailyForecast.precip_prob":    "Maximum precipitation probability (%).",
+		"DailyForecast.sunrise":        "Sunrise.",
+		"DailyForecast.sunset":         "Sunset.",
+	}
+	for _, typ := range []string{"CurrentWeather", "Forecast"} {
+		d[typ+".schema_version"] = "Version of this schema; changes on breaking changes."
+		d[typ+".stale"] = "Served from an expired cache entry (offline mode)."
+		d[typ+".cached_at"] = "When stale data was fetched."
+	}
+	for _, typ := range []string{"CurrentWeather", "HourlyForecast"} {
+		d[typ+".time"] = "Time of the observation or forecast hour, in the location's timezone."
+		d[typ+".temperature"] = "Air temperature at 2 m (units.temperature)."
+		d[typ+".apparent"] = "Feels-like temperature (units.temperature)."
+		d[typ+".humidity"] = "Relative humidity at 2 m (%)."
+		d[typ+".precipitation"] = "Precipitation in the preceding hour (units.precipitation)."
+		d[typ+".rain"] = "Rain in the preceding hour (units.precipitation)."
+		d[typ+".snowfall"] = "Snowfall in the preceding hour (cm, or inch with imperial precipitation)."
+		d[typ+".wind_speed"] = "Wind speed at 10 m (units.wind_speed)."
+		d[typ+".wind_direction"] = "Wind direction at 10 m in degrees (0 = north)."
+		d[typ+".pressure"] = "Sea-level pressure (hPa)."
+		d[typ+".cloud_cover"] = "Total cloud cover (%)."
+		d[typ+".visibility"] = "Visibility (m)."
+		d[typ+".uv_index"] = "UV index."
+	}
+	d["HourlyForecast.precip_prob"] = "Precipitation probability (%)."
+	for _, typ := range []string{"CurrentWeather", "DailyForecast", "HourlyForecast"} {
+		d[typ+".weather_code"] = "WMO weather code; see condition."
+		d[typ+".condition"] = "Description of weather_code."
+	}
+	return d
+}()
@@ -0,0 +1,66 @@
+package weathercli
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOutputSchema(t *testing.T) {
+	for _, name := range SchemaNames() {
+		s, err := OutputSchema(name)
+		if err != nil {
+			t.Fatalf("OutputSchema(%q): %v", name, err)
+		}
+		if _, err := json.Marshal(s); err != nil {
+			t.Errorf("OutputSchema(%q) does not encode: %v", name, err)
+		}
+		// Every $ref must resolve.
+		data, _ := json.Marshal(s)
+		defs, _ := s["$defs"].(map[string]any)
+		for _, part := range strings.Split(string(data), `"$ref":"#/$defs/`)[1:] {
+			def := part[:strings.Index(part, `"`)]
+			if _, ok := defs[def]; !ok {
+				t.Errorf("%s: unresolved $ref %q", name, def)
+			}
+		}
+	}
+
+	s, _ := OutputSchema(SchemaForecast)
+	props := s["properties"].(map[string]any)
+	if got := props["schema_version"].(map[string]any)["const"]; got != SchemaVersion {
+		t.Errorf("schema_version const = %v, want %q", got, SchemaVersion)
+	}
+	required := s["required"].([]string)
+	if !slices.Contains(required, "location") || slices.Contains(required, "daily") {
+		t.Errorf("required = %v, want location but not daily", required)
+	}
+
+	daily := s["$defs"].(map[string]any)["DailyForecast"].(map[string]any)["properties"].(map[string]any)
+	codes := daily["weather_code"].(map[string]any)["enum"].([]any)
+	if len(codes) != len(WeatherCode) || !slices.Contains(codes, any(95)) {
+		t.Errorf("weather_code enum = %v", codes)
+	}
+	if desc := daily["temp_max"].(map[string]any)["description"]; !strings.Contains(desc.(string), "units.temperature") {
+		t.Errorf("temp_max description = %q, want its unit", desc)
+	}
+	if f := daily["sunrise"].(map[string]any)["format"]; f != "date-time" {
+		t.Errorf("sunrise format = %v, want date-time", f)
+	}
+
+	search, _ := OutputSchema(SchemaSearch)
+	if search["type"] != "array" {
+		t.Errorf("search type = %v, want array", search["type"])
+	}
+
+	errSchema, _ := OutputSchema(SchemaError)
+	detail := errSchema["$defs"].(map[string]any)["ErrorDetail"].(map[string]any)["properties"].(map[string]any)
+	if !slices.Contains(detail["code"].(map[string]any)["enum"].([]any), any(ErrorCodeLocationNotFound)) {
+		t.Errorf("error code enum = %v", detail["code"])
+	}
+
+	if _, err := OutputSchema("marine"); err == nil {
+		t.Error("OutputSchema(marine) succeeded, want error")
+	}
+}
@@ -0,0 +1,325 @@
+package weathercli
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// ServerOptions configures NewServer.
+type ServerOptions struct {
+	// CacheTTL is how long responses are kept in memory. Defaults to 5
+	// minutes; negative disables the cache.
+	CacheTTL time.Duration
+
+	// OnRequest, if set, is called after each request with the response
+	// status and the cache outcome: "hit", "miss", "shared" (joined an
+	// identical request in flight) or "" for uncached endpoints.
+	OnRequest func(r *http.Request, status int, cache string, elapsed time.Duration)
+}
+
+// Server is an HTTP handler exposing a Client as a JSON API:
+//
+//	GET /v1/current?q=Berlin
+//	GET /v1/forecast?q=Berlin&days=7&hourly=true
+//	GET /v1/search?q=Berlin&count=5
+//	GET /healthz
+//	GET /openapi.json
+//
+// q is a location name or coordinates. Responses are the types the CLI
+// encodes with --json; errors are an ErrorResponse with its 4xx or 5xx status.
+// Identical requests share one upstream call and its result is cached for
+// CacheTTL.
+type Server struct {
+	client  *Client
+	opts    ServerOptions
+	mux     *http.ServeMux
+	cache   *memoryCache
+	flights flightGroup
+}
+
+// NewServer returns a Server backed by c.
+func NewServer(c *Client, opts ...ServerOptions) *Server {
+	var opt ServerOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.CacheTTL == 0 {
+		opt.CacheTTL = 5 * time.Minute
+	}
+
+	s := &Server{client: c, opts: opt, cache: newMemoryCache(opt.CacheTTL)}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /v1/current", s.cached(s.current))
+	s.mux.HandleFunc("GET /v1/forecast", s.cached(s.forecast))
+	s.mux.HandleFunc("GET /v1/search", s.cached(s.search))
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	s.mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPI)
+	})
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	start := time.Now()
+	s.mux.ServeHTTP(rec, r)
+	if s.opts.OnRequest != nil {
+		s.opts.OnRequest(r, rec.status, rec.Header().Get("X-Cache"), time.Since(start))
+	}
+}
+
+// endpoint handles one API request. key identifies the response for caching
+// and de-duplication; fetch is only called on a cache miss.
+type endpoint func(r *http.Request) (key string, fetch func(ctx context.Context) (any, error), err error)
+
+// cached serves an endpoint through the memory cache and the flight group.
+func (s *Server) cached(e endpoint) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, fetch, err := e(r)
+		if err != nil {
+			writeError(w, &UsageError{Err: err})
+			return
+		}
+
+		if body, age, ok := s.cache.get(key); ok {
+			writeCached(w, body, "hit", s.opts.CacheTTL-age)
+			return
+		}
+		// Shared calls outlive any one caller, so they are not canceled
+		// when the first client goes away.
+		ctx := context.WithoutCancel(r.Context())
+		body, shared, err := s.flights.do(key, func() ([]byte, error) {
+			v, err := fetch(ctx)
+			if err != nil {
+				return nil, err
+			}
+			body, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			s.cache.set(key, body)
+			return body, nil
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		outcome := "miss"
+		if shared {
+			outcome = "shared"
+		}
+		writeCached(w, body, outcome, s.opts.CacheTTL)
+	}
+}
+
+func (s *Server) current(r *http.Request) (string, func(context.Context) (any, error), error) {
+	q, err := queryParam(r)
+	if err != nil {
+		return "", nil, err
+	}
+	return "current|" + normalizeQuery(q), func(ctx context.Context) (any, error) {
+		loc, err := s.client.resolveQuery(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		return s.client.CurrentByCoords(ctx, loc.Latitude, loc.Longitude, &loc)
+	}, nil
+}
+
+func (s *Server) forecast(r *http.Request) (string, func(context.Context) (any, error), error) {
+	q, err := queryParam(r)
+	if err != nil {
+		return "", nil, err
+	}
+	days, err := intParam(r, "days", 7, 1, 16)
+	if err != nil {
+		return "", nil, err
+	}
+	hourly := false
+	if v := r.URL.Query().Get("hourly"); v != "" {
+		if hourly, err = strconv.ParseBool(v); err != nil {
+			return "", nil, fmt.Errorf("hourly must be true or false")
+		}
+	}
+	key := fmt.Sprintf("forecast|%s|%d|%t", normalizeQuery(q), days, hourly)
+	return key, func(ctx context.Context) (any, error) {
+		loc, err := s.client.resolveQuery(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		return s.client.ForecastByCoords(ctx, loc.Latitude, loc.Longitude, days, hourly, &loc)
+	}, nil
+}
+
+func (s *Server) search(r *http.Request) (string, func(context.Context) (any, error), error) {
+	q, err := queryParam(r)
+	if err != nil {
+		return "", nil, err
+	}
+	count, err := intParam(r, "count", 5, 1, 100)
+	if err != nil {
+		return "", nil, err
+	}
+	key := fmt.Sprintf("search|%s|%d", normalizeQuery(q), count)
+	return key, func(ctx context.Context) (any, error) {
+		return s.client.SearchLocation(ctx, q, SearchOptions{Count: count})
+	}, nil
+}
+
+func queryParam(r *http.Request) (string, error) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		return "", fmt.Errorf("missing query parameter q")
+	}
+	return q, nil
+}
+
+func intParam(r *http.Request, name string, fallback, lo, hi int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < lo || n > hi {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, lo, hi)
+	}
+	return n, nil
+}
+
+// normalizeQuery folds queries that geocode the same way onto one key.
+func normalizeQuery(q string) string {
+	return strings.ToLower(strings.Join(strings.Fields(q), " "))
+}
+
+func writeCached(w http.ResponseWriter, body []byte, outcome string, maxAge time.Duration) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Cache", outcome)
+	if maxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	}
+	_, _ = w.Write(body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	resp := NewErrorResponse(err)
+	writeJSON(w, resp.Error.Status, resp)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// memoryCache holds encoded responses for a fixed TTL.
+type memoryCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	body    []byte
+	created time.Time
+}
+
+func newMemoryCache(ttl time.Duration) *memoryCache {
+	return &memoryCache{ttl: ttl, entries: map[string]memoryEntry{}}
+}
+
+// get returns a fresh entry and its age.
+func (c *memoryCache) get(key string) ([]byte, time.Duration, bool) {
+	if c.ttl <= 0 {
+		return nil, 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, 0, false
+	}
+	age := time.Since(e.created)
+	if age >= c.ttl {
+		delete(c.entries, key)
+		return nil, 0, false
+	}
+	return e.body, age, true
+}
+
+// set stores body, dropping expired entries so the cache does not grow
+// without bound.
+func (c *memoryCache) set(key string, body []byte) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.Sub(e.created) >= c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = memoryEntry{body: body, created: now}
+}
+
+// flightGroup runs one call per key at a time; callers arriving while it
+// runs wait for and share its result.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// do runs fn for key unless a call is in flight, and reports whether the
+// result came from another caller's call.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, bool, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flight{}
+	}
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-f.done
+		return f.body, true, f.err
+	}
+	f := &flight{done: make(chan struct{})}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	f.body, f.err = fn()
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(f.done)
+	return f.body, false, f.err
+}
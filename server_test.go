@@ -0,0 +1,111 @@
+package weathercli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	var forecasts atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			if r.URL.Query().Get("name") != "Berlin" {
+				_, _ = w.Write([]byte(`{}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"name":"Berlin","latitude":52.52,"longitude":13.41,"timezone":"Europe/Berlin"}]}`))
+		case "/forecast":
+			forecasts.Add(1)
+			time.Sleep(20 * time.Millisecond) // let concurrent requests pile up
+			_, _ = w.Write([]byte(`{"latitude":52.52,"longitude":13.41,"timezone":"Europe/Berlin","current":{"time":"2024-01-12T09:00","temperature_2m":3.5}}`))
+		}
+	}))
+	defer upstream.Close()
+
+	srv := httptest.NewServer(NewServer(NewClient(Options{BaseURL: upstream.URL, GeoBaseURL: upstream.URL})))
+	defer srv.Close()
+
+	get := func(path string) (*http.Response, []byte) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		return resp, body
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, body := get("/v1/current?q=Berlin")
+			var cw CurrentWeather
+			if err := json.Unmarshal(body, &cw); err != nil || cw.Temperature != 3.5 || cw.Location.Name != "Berlin" {
+				t.Errorf("GET /v1/current: status %d, body %s", resp.StatusCode, body)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := forecasts.Load(); n != 1 {
+		t.Errorf("concurrent requests made %d upstream calls, want 1", n)
+	}
+
+	resp, _ := get("/v1/current?q=%20berlin")
+	if got := resp.Header.Get("X-Cache"); got != "hit" {
+		t.Errorf("X-Cache = %q, want hit", got)
+	}
+	if n := forecasts.Load(); n != 1 {
+		t.Errorf("cached request made %d upstream calls, want 1", n)
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		code   string // error code, for errors
+	}{
+		{"/healthz", http.StatusOK, ""},
+		{"/openapi.json", http.StatusOK, ""},
+		{"/v1/search?q=Berlin&count=3", http.StatusOK, ""},
+		{"/v1/current", http.StatusBadRequest, ErrorCodeInvalidRequest},
+		{"/v1/forecast?q=Berlin&days=20", http.StatusBadRequest, ErrorCodeInvalidRequest},
+		{"/v1/forecast?q=Berlin&hourly=maybe", http.StatusBadRequest, ErrorCodeInvalidRequest},
+		{"/v1/current?q=Nowhere", http.StatusNotFound, ErrorCodeLocationNotFound},
+	}
+	for _, tt := range tests {
+		resp, body := get(tt.path)
+		if resp.StatusCode != tt.status {
+			t.Errorf("GET %s: status %d, want %d (%s)", tt.path, resp.StatusCode, tt.status, body)
+		}
+		if tt.code == "" {
+			continue
+		}
+		var errResp ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error.Code != tt.code || errResp.Error.Status != tt.status {
+			t.Errorf("GET %s: body %s, want code %s", tt.path, body, tt.code)
+		}
+	}
+}
+
+func TestFlightGroupError(t *testing.T) {
+	var g flightGroup
+	_, shared, err := g.do("k", func() ([]byte, error) { return nil, ErrLocationNotFound })
+	if err != ErrLocationNotFound || shared {
+		t.Errorf("do() = shared %v, err %v", shared, err)
+	}
+	body, _, err := g.do("k", func() ([]byte, error) { return []byte("ok"), nil })
+	if err != nil || string(body) != "ok" {
+		t.Errorf("failed call was not forgotten: %q, %v", body, err)
+	}
+}
@@ -4,32 +4,43 @@ import "time"
 
 // Location represents a geographic location with coordinates.
 type Location struct {
-	Name      string  `json:"name"`
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Country   string  `json:"country,omitempty"`
-	Admin1    string  `json:"admin1,omitempty"` // State/Province
-	Timezone  string  `json:"timezone,omitempty"`
+	ID          int64    `json:"id,omitempty"` // GeoNames ID
+	Name        string   `json:"name"`
+	Latitude    float64  `json:"latitude"`
+	Longitude   float64  `json:"longitude"`
+	Elevation   float64  `json:"elevation,omitempty"` // Meters
+	Country     string   `json:"country,omitempty"`
+	CountryCode string   `json:"country_code,omitempty"` // ISO 3166-1 alpha-2
+	Admin1      string   `json:"admin1,omitempty"`       // State/Province
+	Admin2      string   `json:"admin2,omitempty"`       // County/District
+	Timezone    string   `json:"timezone,omitempty"`
+	Population  int      `json:"population,omitempty"`
+	Postcodes   []string `json:"postcodes,omitempty"`
 }
 
 // CurrentWeather represents current weather conditions.
+// Measurements are expressed in Units.
 type CurrentWeather struct {
-	Location      Location  `json:"location"`
-	Time          time.Time `json:"time"`
-	Temperature   float64   `json:"temperature"`   // °C
-	Apparent      float64   `json:"apparent"`      // Feels like °C
-	Humidity      int       `json:"humidity"`      // %
-	Precipitation float64   `json:"precipitation"` // mm
-	Rain          float64   `json:"rain"`          // mm
-	Snowfall      float64   `json:"snowfall"`      // cm
-	WindSpeed     float64   `json:"wind_speed"`    // km/h
-	WindDirection int       `json:"wind_direction"`
-	Pressure      float64   `json:"pressure"` // hPa
-	CloudCover    int       `json:"cloud_cover"`
-	Visibility    float64   `json:"visibility"` // meters
-	UVIndex       float64   `json:"uv_index"`
-	WeatherCode   int       `json:"weather_code"`
-	Condition     string    `json:"condition"` // Human-readable
+	SchemaVersion string     `json:"schema_version"` // SchemaVersion
+	Location      Location   `json:"location"`
+	Units         Units      `json:"units"`
+	Time          time.Time  `json:"time"`
+	Temperature   float64    `json:"temperature"`   // Units.Temperature
+	Apparent      float64    `json:"apparent"`      // Feels like, Units.Temperature
+	Humidity      int        `json:"humidity"`      // %
+	Precipitation float64    `json:"precipitation"` // Units.Precipitation
+	Rain          float64    `json:"rain"`          // Units.Precipitation
+	Snowfall      float64    `json:"snowfall"`      // cm, or inch with imperial precipitation
+	WindSpeed     float64    `json:"wind_speed"`    // Units.WindSpeed
+	WindDirection int        `json:"wind_direction"`
+	Pressure      float64    `json:"pressure"` // hPa
+	CloudCover    int        `json:"cloud_cover"`
+	Visibility    float64    `json:"visibility"` // meters
+	UVIndex       float64    `json:"uv_index"`
+	WeatherCode   int        `json:"weather_code"`
+	Condition     string     `json:"condition"`           // Human-readable
+	Stale         bool       `json:"stale,omitempty"`     // Served from an expired cache entry
+	CachedAt      *time.Time `json:"cached_at,omitempty"` // When a stale response was fetched
 }
 
 // DailyForecast represents a single day's forecast.
@@ -73,10 +84,134 @@ type HourlyForecast struct {
 }
 
 // Forecast represents weather forecast data.
+// Measurements are expressed in Units.
 type Forecast struct {
-	Location Location         `json:"location"`
-	Daily    []DailyForecast  `json:"daily,omitempty"`
-	Hourly   []HourlyForecast `json:"hourly,omitempty"`
+	SchemaVersion string           `json:"schema_version"` // SchemaVersion
+	Location      Location         `json:"location"`
+	Units         Units            `json:"units"`
+	Daily         []DailyForecast  `json:"daily,omitempty"`
+	Hourly        []HourlyForecast `json:"hourly,omitempty"`
+	Stale         bool             `json:"stale,omitempty"`
+	CachedAt      *time.Time       `json:"cached_at,omitempty"`
+}
+
+// MarineForecast represents sea conditions and a marine forecast.
+// Heights are in meters, or feet when Units.Precipitation is inches.
+type MarineForecast struct {
+	SchemaVersion string             `json:"schema_version"` // SchemaVersion
+	Location      Location           `json:"location"`
+	Units         Units              `json:"units"`
+	Current       MarineConditions   `json:"current"`
+	Daily         []MarineDaily      `json:"daily,omitempty"`
+	Hourly        []MarineConditions `json:"hourly,omitempty"`
+	Stale         bool               `json:"stale,omitempty"`
+	CachedAt      *time.Time         `json:"cached_at,omitempty"`
+}
+
+// MarineConditions represents sea conditions at a point in time. Values
+// the API has no data for are nil.
+type MarineConditions struct {
+	Time               time.Time `json:"time"`
+	WaveHeight         *float64  `json:"wave_height,omitempty"`
+	WaveDirection      *int      `json:"wave_direction,omitempty"`
+	WavePeriod         *float64  `json:"wave_period,omitempty"` // seconds
+	SwellWaveHeight    *float64  `json:"swell_wave_height,omitempty"`
+	SwellWaveDirection *int      `json:"swell_wave_direction,omitempty"`
+	SwellWavePeriod    *float64  `json:"swell_wave_period,omitempty"`       // seconds
+	SeaSurfaceTemp     *float64  `json:"sea_surface_temperature,omitempty"` // Units.Temperature
+	SeaLevelHeight     *float64  `json:"sea_level_height,omitempty"`        // Relative to mean sea level
+}
+
+// MarineDaily represents a single day's marine forecast. Values the API
+// has no data for are nil.
+type MarineDaily struct {
+	Date               time.Time `json:"date"`
+	WaveHeightMax      *float64  `json:"wave_height_max,omitempty"`
+	WaveDirection      *int      `json:"wave_direction,omitempty"`
+	WavePeriodMax      *float64  `json:"wave_period_max,omitempty"`
+	SwellWaveHeightMax *float64  `json:"swell_wave_height_max,omitempty"`
+	SwellWaveDirection *int      `json:"swell_wave_direction,omitempty"`
+	SwellWavePeriodMax *float64  `json:"swell_wave_period_max,omitempty"`
+}
+
+// EnsembleForecast summarizes an ensemble forecast: the spread of its
+// members for each day or hour. Measurements are expressed in Units.
+type EnsembleForecast struct {
+	SchemaVersion   string         `json:"schema_version"` // SchemaVersion
+	Location        Location       `json:"location"`
+	Units           Units          `json:"units"`
+	Models          []string       `json:"models"`
+	Members         int            `json:"members"`          // Across all models
+	PrecipThreshold float64        `json:"precip_threshold"` // Units.Precipitation, per hour or day
+	Daily           []EnsembleDay  `json:"daily,omitempty"`
+	Hourly          []EnsembleHour `json:"hourly,omitempty"`
+	Stale           bool           `json:"stale,omitempty"`
+	CachedAt        *time.Time     `json:"cached_at,omitempty"`
+}
+
+// EnsembleStats is the distribution of a value across ensemble members.
+type EnsembleStats struct {
+	Mean float64 `json:"mean"`
+	Min  float64 `json:"min"`
+	P10  float64 `json:"p10"` // 10th percentile
+	P90  float64 `json:"p90"` // 90th percentile
+	Max  float64 `json:"max"`
+}
+
+// EnsembleDay represents a single day of an ensemble forecast.
+type EnsembleDay struct {
+	Date          time.Time     `json:"date"`
+	TempMax       EnsembleStats `json:"temp_max"`
+	TempMin       EnsembleStats `json:"temp_min"`
+	Precipitation EnsembleStats `json:"precipitation"` // Daily total
+	WindSpeedMax  EnsembleStats `json:"wind_speed_max"`
+	PrecipProb    int           `json:"precip_prob"` // % of members above PrecipThreshold
+}
+
+// EnsembleHour represents a single hour of an ensemble forecast.
+type EnsembleHour struct {
+	Time          time.Time     `json:"time"`
+	Temperature   EnsembleStats `json:"temperature"`
+	Precipitation EnsembleStats `json:"precipitation"`
+	WindSpeed     EnsembleStats `json:"wind_speed"`
+	PrecipProb    int           `json:"precip_prob"` // % of members above PrecipThreshold
+}
+
+// AirQuality represents current air quality and an hourly forecast.
+type AirQuality struct {
+	SchemaVersion string              `json:"schema_version"` // SchemaVersion
+	Location      Location            `json:"location"`
+	Current       AirQualityReading   `json:"current"`
+	Hourly        []AirQualityReading `json:"hourly,omitempty"`
+	Stale         bool                `json:"stale,omitempty"`
+	CachedAt      *time.Time          `json:"cached_at,omitempty"`
+}
+
+// AirQualityReading represents air quality at a point in time.
+// Pollutant concentrations are in μg/m³. Readings the API has no data for
+// are nil.
+type AirQualityReading struct {
+	Time            time.Time `json:"time"`
+	PM25            *float64  `json:"pm2_5,omitempty"`
+	PM10            *float64  `json:"pm10,omitempty"`
+	Ozone           *float64  `json:"ozone,omitempty"`
+	NitrogenDioxide *float64  `json:"nitrogen_dioxide,omitempty"`
+	SulphurDioxide  *float64  `json:"sulphur_dioxide,omitempty"`
+	CarbonMonoxide  *float64  `json:"carbon_monoxide,omitempty"`
+	EuropeanAQI     *int      `json:"european_aqi,omitempty"`
+	USAQI           *int      `json:"us_aqi,omitempty"`
+	Pollen          *Pollen   `json:"pollen,omitempty"` // Europe only
+}
+
+// Pollen represents pollen concentrations in grains/m³. Species that are
+// not modelled for the location are nil.
+type Pollen struct {
+	Alder   *float64 `json:"alder,omitempty"`
+	Birch   *float64 `json:"birch,omitempty"`
+	Grass   *float64 `json:"grass,omitempty"`
+	Mugwort *float64 `json:"mugwort,omitempty"`
+	Olive   *float64 `json:"olive,omitempty"`
+	Ragweed *float64 `json:"ragweed,omitempty"`
 }
 
 // WeatherCode maps WMO weather codes to human-readable conditions.
@@ -130,3 +265,39 @@ func WindDirection(degrees int) string {
 	idx := int((float64(normalized) + 11.25) / 22.5)
 	return dirs[idx%16]
 }
+
+// EuropeanAQILevel returns the European AQI band for an index value.
+func EuropeanAQILevel(aqi int) string {
+	switch {
+	case aqi <= 20:
+		return "Good"
+	case aqi <= 40:
+		return "Fair"
+	case aqi <= 60:
+		return "Moderate"
+	case aqi <= 80:
+		return "Poor"
+	case aqi <= 100:
+		return "Very poor"
+	default:
+		return "Extremely poor"
+	}
+}
+
+// USAQILevel returns the US EPA AQI category for an index value.
+func USAQILevel(aqi int) string {
+	switch {
+	case aqi <= 50:
+		return "Good"
+	case aqi <= 100:
+		return "Moderate"
+	case aqi <= 150:
+		return "Unhealthy for sensitive groups"
+	case aqi <= 200:
+		return "Unhealthy"
+	case aqi <= 300:
+		return "Very unhealthy"
+	default:
+		return "Hazardous"
+	}
+}
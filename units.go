@@ -0,0 +1,237 @@
+package weathercli
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TemperatureUnit is an Open-Meteo temperature unit.
+type TemperatureUnit string
+
+// WindSpeedUnit is an Open-Meteo wind speed unit.
+type WindSpeedUnit string
+
+// PrecipitationUnit is an Open-Meteo precipitation unit.
+type PrecipitationUnit string
+
+// Supported units.
+const (
+	Celsius    TemperatureUnit = "celsius"
+	Fahrenheit TemperatureUnit = "fahrenheit"
+
+	KilometersPerHour WindSpeedUnit = "kmh"
+	MetersPerSecond   WindSpeedUnit = "ms"
+	MilesPerHour      WindSpeedUnit = "mph"
+	Knots             WindSpeedUnit = "kn"
+
+	Millimeters PrecipitationUnit = "mm"
+	Inches      PrecipitationUnit = "inch"
+)
+
+// Unit system names.
+const (
+	UnitSystemMetric   = "metric"
+	UnitSystemImperial = "imperial"
+	UnitSystemCustom   = "custom"
+)
+
+// Units describes the measurement units of a response.
+type Units struct {
+	System        string            `json:"system"` // metric, imperial or custom
+	Temperature   TemperatureUnit   `json:"temperature"`
+	WindSpeed     WindSpeedUnit     `json:"wind_speed"`
+	Precipitation PrecipitationUnit `json:"precipitation"`
+}
+
+// MetricUnits returns the Open-Meteo defaults: °C, km/h and mm.
+func MetricUnits() Units {
+	return Units{
+		System:        UnitSystemMetric,
+		Temperature:   Celsius,
+		WindSpeed:     KilometersPerHour,
+		Precipitation: Millimeters,
+	}
+}
+
+// ImperialUnits returns °F, mph and inches.
+func ImperialUnits() Units {
+	return Units{
+		System:        UnitSystemImperial,
+		Temperature:   Fahrenheit,
+		WindSpeed:     MilesPerHour,
+		Precipitation: Inches,
+	}
+}
+
+// ParseUnits builds units from a system name and optional per-quantity
+// overrides. Empty overrides keep the system default. A "custom" system
+// starts from metric. If an override departs from the named system, the
+// result is reported as custom.
+func ParseUnits(system, temperature, wind, precipitation string) (Units, error) {
+	var u Units
+	switch strings.ToLower(system) {
+	case "", UnitSystemMetric, UnitSystemCustom:
+		u = MetricUnits()
+	case UnitSystemImperial:
+		u = ImperialUnits()
+	default:
+		return Units{}, fmt.Errorf("unknown unit system %q (want metric, imperial or custom)", system)
+	}
+	base := u
+
+	if temperature != "" {
+		t, err := parseTemperatureUnit(temperature)
+		if err != nil {
+			return Units{}, err
+		}
+		u.Temperature = t
+	}
+	if wind != "" {
+		w, err := parseWindSpeedUnit(wind)
+		if err != nil {
+			return Units{}, err
+		}
+		u.WindSpeed = w
+	}
+	if precipitation != "" {
+		p, err := parsePrecipitationUnit(precipitation)
+		if err != nil {
+			return Units{}, err
+		}
+		u.Precipitation = p
+	}
+
+	if strings.EqualFold(system, UnitSystemCustom) || u != base {
+		u.System = UnitSystemCustom
+	}
+	return u, nil
+}
+
+func parseTemperatureUnit(s string) (TemperatureUnit, error) {
+	switch strings.ToLower(s) {
+	case "c", "celsius":
+		return Celsius, nil
+	case "f", "fahrenheit":
+		return Fahrenheit, nil
+	}
+	return "", fmt.Errorf("unknown temperature unit %q (want c or f)", s)
+}
+
+func parseWindSpeedUnit(s string) (WindSpeedUnit, error) {
+	switch strings.ToLower(s) {
+	case "kmh", "km/h":
+		return KilometersPerHour, nil
+	case "ms", "m/s":
+		return MetersPerSecond, nil
+	case "mph":
+		return MilesPerHour, nil
+	case "kn", "kt", "knots":
+		return Knots, nil
+	}
+	return "", fmt.Errorf("unknown wind speed unit %q (want kmh, ms, mph or kn)", s)
+}
+
+func parsePrecipitationUnit(s string) (PrecipitationUnit, error) {
+	switch strings.ToLower(s) {
+	case "mm":
+		return Millimeters, nil
+	case "in", "inch", "inches":
+		return Inches, nil
+	}
+	return "", fmt.Errorf("unknown precipitation unit %q (want mm or inch)", s)
+}
+
+// orDefault returns metric units when u is the zero value.
+func (u Units) orDefault() Units {
+	if u == (Units{}) {
+		return MetricUnits()
+	}
+	return u
+}
+
+// TemperatureSymbol returns the display symbol for temperatures.
+func (u Units) TemperatureSymbol() string {
+	if u.Temperature == Fahrenheit {
+		return "°F"
+	}
+	return "°C"
+}
+
+// WindSpeedSymbol returns the display symbol for wind speeds.
+func (u Units) WindSpeedSymbol() string {
+	switch u.WindSpeed {
+	case MetersPerSecond:
+		return "m/s"
+	case MilesPerHour:
+		return "mph"
+	case Knots:
+		return "kn"
+	default:
+		return "km/h"
+	}
+}
+
+// PrecipitationSymbol returns the display symbol for rain and precipitation.
+func (u Units) PrecipitationSymbol() string {
+	if u.Precipitation == Inches {
+		return "in"
+	}
+	return "mm"
+}
+
+// SnowfallSymbol returns the display symbol for snowfall. Open-Meteo reports
+// snowfall in cm, or in inches when the precipitation unit is inches.
+func (u Units) SnowfallSymbol() string {
+	if u.Precipitation == Inches {
+		return "in"
+	}
+	return "cm"
+}
+
+// LengthSymbol returns the display symbol for heights such as waves.
+// Lengths follow the precipitation unit: meters with mm, feet with inches.
+func (u Units) LengthSymbol() string {
+	if u.Precipitation == Inches {
+		return "ft"
+	}
+	return "m"
+}
+
+// lengthUnit returns the Open-Meteo length_unit parameter.
+func (u Units) lengthUnit() string {
+	if u.Precipitation == Inches {
+		return "imperial"
+	}
+	return "metric"
+}
+
+// ToCelsius converts a temperature in these units to °C.
+func (u Units) ToCelsius(temp float64) float64 {
+	if u.Temperature == Fahrenheit {
+		return (temp - 32) * 5 / 9
+	}
+	return temp
+}
+
+// ToKmh converts a wind speed in these units to km/h.
+func (u Units) ToKmh(speed float64) float64 {
+	switch u.WindSpeed {
+	case MetersPerSecond:
+		return speed * 3.6
+	case MilesPerHour:
+		return speed * 1.609344
+	case Knots:
+		return speed * 1.852
+	default:
+		return speed
+	}
+}
+
+// setQuery adds the Open-Meteo unit parameters to q.
+func (u Units) setQuery(q url.Values) {
+	u = u.orDefault()
+	q.Set("temperature_unit", string(u.Temperature))
+	q.Set("wind_speed_unit", string(u.WindSpeed))
+	q.Set("precipitation_unit", string(u.Precipitation))
+}
@@ -0,0 +1,86 @@
+package weathercli
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		name               string
+		system, temp, wind string
+		precip             string
+		want               Units
+		wantErr            bool
+	}{
+		{
+			name: "default is metric",
+			want: MetricUnits(),
+		},
+		{
+			name:   "imperial",
+			system: "imperial",
+			want:   ImperialUnits(),
+		},
+		{
+			name:   "metric with knots becomes custom",
+			system: "metric",
+			wind:   "kn",
+			want:   Units{System: UnitSystemCustom, Temperature: Celsius, WindSpeed: Knots, Precipitation: Millimeters},
+		},
+		{
+			name:   "override matching the system stays imperial",
+			system: "imperial",
+			temp:   "f",
+			want:   ImperialUnits(),
+		},
+		{
+			name:   "custom starts from metric",
+			system: "custom",
+			temp:   "fahrenheit",
+			precip: "in",
+			want:   Units{System: UnitSystemCustom, Temperature: Fahrenheit, WindSpeed: KilometersPerHour, Precipitation: Inches},
+		},
+		{name: "unknown system", system: "nautical", wantErr: true},
+		{name: "unknown temperature", temp: "k", wantErr: true},
+		{name: "unknown wind", wind: "beaufort", wantErr: true},
+		{name: "unknown precipitation", precip: "cm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnits(tt.system, tt.temp, tt.wind, tt.precip)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseUnits() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUnits() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseUnits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitConversions(t *testing.T) {
+	imperial := ImperialUnits()
+	if got := imperial.ToCelsius(86); math.Abs(got-30) > 1e-9 {
+		t.Errorf("ToCelsius(86°F) = %v, want 30", got)
+	}
+	if got := MetricUnits().ToCelsius(21.5); got != 21.5 {
+		t.Errorf("ToCelsius(21.5°C) = %v, want 21.5", got)
+	}
+	if got := (Units{WindSpeed: MetersPerSecond}).ToKmh(10); math.Abs(got-36) > 1e-9 {
+		t.Errorf("ToKmh(10 m/s) = %v, want 36", got)
+	}
+	if got := imperial.TemperatureSymbol(); got != "°F" {
+		t.Errorf("TemperatureSymbol() = %q, want °F", got)
+	}
+	if got := imperial.SnowfallSymbol(); got != "in" {
+		t.Errorf("SnowfallSymbol() = %q, want in", got)
+	}
+}
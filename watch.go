@@ -0,0 +1,135 @@
+package weathercli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Update is one refresh of Watch. Exactly one of Weather and Err is set.
+type Update struct {
+	Time    time.Time       // when the refresh happened
+	Weather *CurrentWeather // nil if the refresh failed
+	Err     error
+	// Changed reports whether conditions differ from the last successful
+	// update. The first successful update is always changed.
+	Changed bool
+}
+
+// ForecastUpdate is one refresh of WatchForecast, like Update.
+type ForecastUpdate struct {
+	Time     time.Time
+	Forecast *Forecast
+	Err      error
+	Changed  bool
+}
+
+// Watch fetches current weather for loc now and then every interval until
+// ctx is done, when the channel is closed. Refreshes bypass fresh cache
+// entries. A failed refresh is delivered as an Update with Err set and the
+// watch continues. Updates are not buffered: a slow consumer delays the
+// next refresh.
+func (c *Client) Watch(ctx context.Context, loc Location, interval time.Duration) <-chan Update {
+	ch := make(chan Update)
+	go func() {
+		defer close(ch)
+		var last *CurrentWeather
+		watch(ctx, interval, func(ctx context.Context) bool {
+			u := Update{Time: time.Now()}
+			u.Weather, u.Err = c.CurrentByCoords(ctx, loc.Latitude, loc.Longitude, &loc)
+			if u.Err == nil {
+				u.Changed = last == nil || !sameConditions(*last, *u.Weather)
+				last = u.Weather
+			}
+			return send(ctx, ch, u)
+		}, func(err error) {
+			send(ctx, ch, Update{Time: time.Now(), Err: err})
+		})
+	}()
+	return ch
+}
+
+// WatchForecast is Watch for the forecast of loc; see ForecastByCoords for
+// days and hourly.
+func (c *Client) WatchForecast(ctx context.Context, loc Location, days int, hourly bool, interval time.Duration) <-chan ForecastUpdate {
+	ch := make(chan ForecastUpdate)
+	go func() {
+		defer close(ch)
+		var last *Forecast
+		watch(ctx, interval, func(ctx context.Context) bool {
+			u := ForecastUpdate{Time: time.Now()}
+			u.Forecast, u.Err = c.ForecastByCoords(ctx, loc.Latitude, loc.Longitude, days, hourly, &loc)
+			if u.Err == nil {
+				u.Changed = last == nil || !sameForecast(*last, *u.Forecast)
+				last = u.Forecast
+			}
+			return send(ctx, ch, u)
+		}, func(err error) {
+			send(ctx, ch, ForecastUpdate{Time: time.Now(), Err: err})
+		})
+	}()
+	return ch
+}
+
+// watch calls refresh immediately and then every interval until ctx is
+// done or refresh returns false. An invalid interval is reported through
+// fail.
+func watch(ctx context.Context, interval time.Duration, refresh func(context.Context) bool, fail func(error)) {
+	if interval <= 0 {
+		fail(fmt.Errorf("watch interval must be positive, got %s", interval))
+		return
+	}
+	ctx = context.WithValue(ctx, refreshKey{}, true)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if !refresh(ctx) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// send delivers v unless ctx is done, in which case v is most likely a
+// cancellation error.
+func send[T any](ctx context.Context, ch chan<- T, v T) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// refreshKey marks contexts whose requests skip fresh cache entries.
+type refreshKey struct{}
+
+func refreshing(ctx context.Context) bool {
+	v, _ := ctx.Value(refreshKey{}).(bool)
+	return v
+}
+
+// sameConditions reports whether two readings have the same conditions,
+// ignoring the observation time and cache state.
+func sameConditions(a, b CurrentWeather) bool {
+	a.Time, a.Stale, a.CachedAt = time.Time{}, false, nil
+	b.Time, b.Stale, b.CachedAt = time.Time{}, false, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// sameForecast reports whether two forecasts have the same data, ignoring
+// cache state.
+func sameForecast(a, b Forecast) bool {
+	a.Stale, a.CachedAt = false, nil
+	b.Stale, b.CachedAt = false, nil
+	return reflect.DeepEqual(a, b)
+}
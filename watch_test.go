@@ -0,0 +1,58 @@
+package weathercli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	temps := []float64{10, 10, 12}
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1)) - 1
+		temp := temps[min(n, len(temps)-1)]
+		fmt.Fprintf(w, `{"latitude":52.52,"longitude":13.41,"timezone":"UTC","current":{"time":"2024-01-12T09:%02d","temperature_2m":%g}}`, n, temp)
+	}))
+	defer srv.Close()
+
+	// The cache must not hide refreshes.
+	client := NewClient(Options{BaseURL: srv.URL, CacheDir: t.TempDir(), CacheTTL: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := client.Watch(ctx, Location{Name: "Berlin", Latitude: 52.52, Longitude: 13.41}, 10*time.Millisecond)
+	var changed []bool
+	for u := range updates {
+		if u.Err != nil {
+			t.Fatalf("update error: %v", u.Err)
+		}
+		changed = append(changed, u.Changed)
+		if len(changed) == len(temps) {
+			cancel()
+		}
+	}
+
+	want := []bool{true, false, true}
+	if fmt.Sprint(changed) != fmt.Sprint(want) {
+		t.Errorf("Changed = %v, want %v", changed, want)
+	}
+	if n := requests.Load(); n != int32(len(temps)) && n != int32(len(temps))+1 {
+		t.Errorf("made %d requests, want %d", n, len(temps))
+	}
+}
+
+func TestWatchInvalidInterval(t *testing.T) {
+	client := NewClient()
+	var updates []Update
+	for u := range client.Watch(context.Background(), Location{}, 0) {
+		updates = append(updates, u)
+	}
+	if len(updates) != 1 || updates[0].Err == nil {
+		t.Errorf("updates = %+v, want a single error", updates)
+	}
+}